@@ -0,0 +1,147 @@
+package chanx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xoctopus/x/container/queue"
+)
+
+// WithAsync makes Send enqueue values and return immediately. each subscriber
+// has its own queue and delivering goroutine, so values are delivered in FIFO
+// order per subscriber, and a slow subscriber does not block others. values
+// pending in the queue are discarded when the subscriber is done. use Flush to
+// wait for all queues being drained.
+func WithAsync[T any]() SubjectOption[T] {
+	return func(s *Subject[T]) {
+		s.async = true
+	}
+}
+
+// Flush waits until all values sent before are delivered to subscribers, or
+// returns context.Cause(ctx) when ctx is done. it returns immediately if s is
+// not in async mode.
+func (s *Subject[T]) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	boxes := make([]*mailbox[T], 0, len(s.subs))
+	for _, mb := range s.subs {
+		if mb != nil {
+			boxes = append(boxes, mb)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, mb := range boxes {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-mb.drained():
+		}
+	}
+	return nil
+}
+
+// dispatch delivers values from mb to o in order until o is done
+func (s *Subject[T]) dispatch(o Subscriber[T], mb *mailbox[T]) {
+	for {
+		select {
+		case <-o.Done():
+			for range mb.close() {
+				s.record(o, 0, true)
+			}
+			return
+		case <-mb.signal:
+		}
+		for {
+			x, ok := mb.pop()
+			if !ok {
+				break
+			}
+			s.deliver(o, x)
+			mb.ack()
+		}
+	}
+}
+
+func newMailbox[T any]() *mailbox[T] {
+	return &mailbox[T]{
+		values: queue.NewQueue[T](),
+		signal: make(chan struct{}, 1),
+	}
+}
+
+// mailbox is an unbounded queue of values pending delivery to a subscriber
+type mailbox[T any] struct {
+	mu      sync.Mutex
+	values  queue.Queue[T]
+	signal  chan struct{}
+	pending int // queued and delivering values
+	idle    chan struct{}
+	closed  bool
+}
+
+func (m *mailbox[T]) push(x T) bool {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return false
+	}
+	m.values.Push(x)
+	m.pending++
+	m.mu.Unlock()
+
+	select {
+	case m.signal <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (m *mailbox[T]) pop() (T, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.values.Pop()
+}
+
+// ack marks a popped value delivered
+func (m *mailbox[T]) ack() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pending--; m.pending == 0 && m.idle != nil {
+		close(m.idle)
+		m.idle = nil
+	}
+}
+
+// drained returns a channel closed when no value is pending
+func (m *mailbox[T]) drained() <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pending == 0 {
+		return closedch
+	}
+	if m.idle == nil {
+		m.idle = make(chan struct{})
+	}
+	return m.idle
+}
+
+// close rejects further values and returns the discarded ones
+func (m *mailbox[T]) close() []T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	discarded := make([]T, 0, m.values.Len())
+	for x, ok := m.values.Pop(); ok; x, ok = m.values.Pop() {
+		discarded = append(discarded, x)
+	}
+	m.closed = true
+	m.pending = 0
+	if m.idle != nil {
+		close(m.idle)
+		m.idle = nil
+	}
+	return discarded
+}
@@ -0,0 +1,76 @@
+package chanx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xoctopus/x/chanx"
+	. "github.com/xoctopus/x/testx"
+)
+
+func TestWithAsync(t *testing.T) {
+	s := chanx.NewSubject(chanx.WithAsync[int]())
+
+	fast := chanx.NewBufferedObserver[int](100, chanx.OverflowBlock)
+	slow := &slowSubscriber{chanx.NewBufferedObserver[int](100, chanx.OverflowBlock)}
+	s.Subscribe(fast)
+	s.Subscribe(slow)
+
+	start := time.Now()
+	for i := range 5 {
+		s.Send(i)
+	}
+	Expect(t, time.Since(start), BeLt(20*time.Millisecond))
+
+	for i := range 5 {
+		Expect(t, <-fast.Value(), Equal(i))
+	}
+	Expect(t, time.Since(start), BeLt(20*time.Millisecond))
+
+	Expect(t, s.Flush(context.Background()), Succeed())
+	Expect(t, time.Since(start), BeGte(100*time.Millisecond))
+	for i := range 5 {
+		Expect(t, <-slow.Value(), Equal(i))
+	}
+
+	stats := s.Stats()
+	Expect(t, stats.Sent, Equal[uint64](5))
+	Expect(t, stats.Delivered, Equal[uint64](10))
+
+	t.Run("FlushTimeout", func(t *testing.T) {
+		s.Send(5)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		Expect(t, s.Flush(ctx), IsError(context.DeadlineExceeded))
+		Expect(t, s.Flush(context.Background()), Succeed())
+	})
+	t.Run("DiscardOnDone", func(t *testing.T) {
+		for i := range 5 {
+			s.Send(i)
+		}
+		slow.CancelCause(nil)
+		Expect(t, s.Flush(context.Background()), Succeed())
+		Expect(t, s.Stats().Dropped, BeGt[uint64](0))
+	})
+	t.Run("SubscribeTwice", func(t *testing.T) {
+		s := chanx.NewSubject(chanx.WithAsync[int]())
+		o := chanx.NewBufferedObserver[int](2, chanx.OverflowBlock)
+		s.Subscribe(o)
+		s.Subscribe(o)
+		Expect(t, s.Stats().Subscribers, Equal(1))
+
+		s.Send(1)
+		s.Send(2)
+		Expect(t, s.Flush(context.Background()), Succeed())
+		Expect(t, <-o.Value(), Equal(1))
+		Expect(t, <-o.Value(), Equal(2))
+		Expect(t, s.Stats().Delivered, Equal[uint64](2))
+		s.CancelCause(nil)
+	})
+	t.Run("Sync", func(t *testing.T) {
+		Expect(t, (&chanx.Subject[int]{}).Flush(context.Background()), Succeed())
+	})
+
+	s.CancelCause(nil)
+}
@@ -0,0 +1,157 @@
+package chanx
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Hub routes values to subscribers by topic. topics are hierarchical tokens
+// separated by '.', and a subscribing pattern supports wildcards:
+//
+//	'*' matches exactly one token. eg: `orders.*` matches `orders.created`
+//	'>' matches one or more tailing tokens. eg: `orders.>` matches `orders.a.b`
+//
+// a Subject is created lazily for each pattern and released when its last
+// subscriber leaves. a zero Hub is ready to use.
+type Hub[K ~string, T any] struct {
+	mu     sync.Mutex
+	err    error
+	topics map[K]*topic[T]
+}
+
+type topic[T any] struct {
+	subject Subject[T]
+	refs    int
+}
+
+func (h *Hub[K, T]) Err() error {
+	h.mu.Lock()
+	err := h.err
+	h.mu.Unlock()
+	return err
+}
+
+// CancelCause cancels all subscribers and rejects further subscribing
+func (h *Hub[K, T]) CancelCause(err error) {
+	h.mu.Lock()
+	if h.err != nil {
+		h.mu.Unlock()
+		return // already canceled
+	}
+
+	if err == nil {
+		err = ErrCompleted
+	}
+	h.err = err
+
+	topics := h.topics
+	h.topics = nil
+	h.mu.Unlock()
+
+	for _, t := range topics {
+		t.subject.CancelCause(err)
+	}
+}
+
+// Topics returns patterns which have subscribers
+func (h *Hub[K, T]) Topics() []K {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	patterns := make([]K, 0, len(h.topics))
+	for pattern := range h.topics {
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// Send delivers x to subscribers whose pattern matches topic k
+func (h *Hub[K, T]) Send(k K, x T) {
+	h.mu.Lock()
+	if h.err != nil {
+		h.mu.Unlock()
+		return // already canceled
+	}
+
+	subjects := make([]*Subject[T], 0, 1)
+	for pattern, t := range h.topics {
+		if match(string(pattern), string(k)) {
+			subjects = append(subjects, &t.subject)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, s := range subjects {
+		s.Send(x)
+	}
+}
+
+func (h *Hub[K, T]) Observe(pattern K) Observer[T] {
+	return h.ObserveContext(context.Background(), pattern)
+}
+
+func (h *Hub[K, T]) ObserveContext(ctx context.Context, pattern K) Observer[T] {
+	o := NewNotifiableObserver[T]()
+	h.SubscribeContext(ctx, pattern, o)
+	return o
+}
+
+func (h *Hub[K, T]) Subscribe(pattern K, o Subscriber[T]) {
+	h.SubscribeContext(context.Background(), pattern, o)
+}
+
+// SubscribeContext subscribes o to topics matching pattern. o is unsubscribed
+// when it is done or canceled by context.Cause(ctx) when ctx is done.
+func (h *Hub[K, T]) SubscribeContext(ctx context.Context, pattern K, o Subscriber[T]) {
+	h.mu.Lock()
+	if h.err != nil {
+		err := h.err
+		h.mu.Unlock()
+		o.CancelCause(err)
+		return // already canceled
+	}
+
+	if h.topics == nil {
+		h.topics = map[K]*topic[T]{}
+	}
+	t := h.topics[pattern]
+	if t == nil {
+		t = &topic[T]{}
+		h.topics[pattern] = t
+	}
+	t.refs++
+	h.mu.Unlock()
+
+	t.subject.SubscribeContext(ctx, o)
+
+	go func() {
+		<-o.Done()
+
+		h.mu.Lock()
+		if t.refs--; t.refs == 0 && h.topics[pattern] == t {
+			delete(h.topics, pattern)
+		}
+		h.mu.Unlock()
+	}()
+}
+
+// match reports whether topic matches pattern
+func match(pattern, topic string) bool {
+	if pattern == topic {
+		return true
+	}
+	for {
+		p, prest, pmore := strings.Cut(pattern, ".")
+		t, trest, tmore := strings.Cut(topic, ".")
+		switch {
+		case p == ">":
+			return !pmore && len(topic) > 0
+		case p != "*" && p != t:
+			return false
+		case !pmore || !tmore:
+			return pmore == tmore
+		}
+		pattern, topic = prest, trest
+	}
+}
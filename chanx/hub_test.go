@@ -0,0 +1,120 @@
+package chanx_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xoctopus/x/chanx"
+	. "github.com/xoctopus/x/testx"
+)
+
+func ExampleHub() {
+	hub := &chanx.Hub[string, string]{}
+
+	created := hub.Observe("orders.created")
+	all := hub.Observe("orders.>")
+
+	go hub.Send("orders.created", "order#1")
+	fmt.Println("created:", <-created.Value())
+	fmt.Println("all:", <-all.Value())
+
+	go hub.Send("orders.paid.online", "order#1")
+	fmt.Println("all:", <-all.Value())
+
+	hub.CancelCause(nil)
+	fmt.Println(created.Err(), all.Err())
+
+	// Output:
+	// created: order#1
+	// all: order#1
+	// all: order#1
+	// completed completed
+}
+
+func TestHub(t *testing.T) {
+	t.Run("Routing", func(t *testing.T) {
+		patterns := []string{
+			"orders",
+			"orders.created",
+			"orders.*",
+			"orders.>",
+			"*.created",
+			">",
+			"orders.*.online",
+		}
+		cases := []struct {
+			topic   string
+			matched []string
+		}{
+			{"orders", []string{"orders", ">"}},
+			{"orders.created", []string{"orders.created", "orders.*", "orders.>", "*.created", ">"}},
+			{"orders.paid.online", []string{"orders.>", ">", "orders.*.online"}},
+			{"users.created", []string{"*.created", ">"}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.topic, func(t *testing.T) {
+				hub := &chanx.Hub[string, string]{}
+				observers := make(map[string]chanx.BufferedObserver[string])
+				for _, p := range patterns {
+					o := chanx.NewBufferedObserver[string](1, chanx.OverflowBlock)
+					hub.Subscribe(p, o)
+					observers[p] = o
+				}
+				hub.Send(c.topic, c.topic)
+
+				for p, o := range observers {
+					select {
+					case v := <-o.Value():
+						Expect(t, slices.Contains(c.matched, p), BeTrue())
+						Expect(t, v, Equal(c.topic))
+					case <-time.After(10 * time.Millisecond):
+						Expect(t, slices.Contains(c.matched, p), BeFalse())
+					}
+				}
+				hub.CancelCause(nil)
+			})
+		}
+	})
+	t.Run("ReleaseTopics", func(t *testing.T) {
+		hub := &chanx.Hub[string, int]{}
+		o1 := hub.Observe("a.b")
+		o2 := hub.Observe("a.b")
+		o3 := hub.Observe("a.*")
+		Expect(t, slices.Sorted(slices.Values(hub.Topics())), Equal([]string{"a.*", "a.b"}))
+
+		o1.CancelCause(nil)
+		time.Sleep(10 * time.Millisecond)
+		Expect(t, slices.Sorted(slices.Values(hub.Topics())), Equal([]string{"a.*", "a.b"}))
+
+		o2.CancelCause(nil)
+		o3.CancelCause(nil)
+		time.Sleep(10 * time.Millisecond)
+		Expect(t, hub.Topics(), HaveLen[[]string](0))
+
+		o4 := hub.Observe("a.b")
+		go hub.Send("a.b", 1)
+		Expect(t, <-o4.Value(), Equal(1))
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		cause := errors.New("closed")
+		hub := &chanx.Hub[string, int]{}
+		o1 := hub.Observe("a")
+		hub.CancelCause(cause)
+		hub.CancelCause(nil)
+		Expect(t, hub.Err(), IsError(cause))
+
+		<-o1.Done()
+		Expect(t, o1.Err(), IsError(cause))
+
+		o2 := hub.Observe("a")
+		<-o2.Done()
+		Expect(t, o2.Err(), IsError(cause))
+
+		hub.Send("a", 1)
+		Expect(t, hub.Topics(), HaveLen[[]string](0))
+	})
+}
@@ -2,14 +2,43 @@ package chanx
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 )
 
+// OverflowPolicy defines how a buffered observer behaves when its buffer is full
+type OverflowPolicy int8
+
+const (
+	// OverflowBlock blocks Send until the buffer has free space
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the value being sent
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest buffered value to make room
+	OverflowDropOldest
+	// OverflowCancel cancels the observer with ErrOverflow
+	OverflowCancel
+)
+
 func NewNotifiableObserver[T any]() NotifiableObserver[T] {
+	return newObserver[T](0, OverflowBlock)
+}
+
+// NewBufferedObserver creates an observer buffering up to size values. when the
+// buffer is full, Send follows policy instead of blocking the publisher.
+func NewBufferedObserver[T any](size int, policy OverflowPolicy) BufferedObserver[T] {
+	return newObserver[T](size, policy)
+}
+
+func newObserver[T any](size int, policy OverflowPolicy) *observer[T] {
+	if policy != OverflowBlock {
+		size = max(size, 1) // non-blocking policies need room to overflow
+	}
 	o := &observer[T]{
-		value: make(chan T),
-		in:    make(chan T),
+		value:  make(chan T),
+		in:     make(chan T, max(size, 0)),
+		policy: policy,
 	}
 	go func() {
 		defer close(o.value)
@@ -33,18 +62,36 @@ func NewNotifiableObserver[T any]() NotifiableObserver[T] {
 var (
 	closedch     = make(chan struct{})
 	ErrCompleted = errors.New("completed")
+	ErrOverflow  = errors.New("overflow")
 )
 
+// PanicError wraps a value recovered from a panicking subscriber
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("subscriber panicked: %v", e.Value)
+}
+
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
 func init() {
 	close(closedch)
 }
 
 type observer[T any] struct {
-	value chan T
-	in    chan T
-	mu    sync.Mutex
-	done  atomic.Value
-	err   error
+	value   chan T
+	in      chan T
+	policy  OverflowPolicy
+	dropped atomic.Uint64
+	mu      sync.Mutex
+	done    atomic.Value
+	err     error
 }
 
 func (o *observer[T]) Value() <-chan T {
@@ -66,12 +113,61 @@ func (o *observer[T]) Send(x T) {
 	}
 	o.mu.Unlock()
 
+	switch o.policy {
+	case OverflowDropNewest:
+		select {
+		case <-o.Done():
+		case o.in <- x:
+		default:
+			o.dropped.Add(1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case <-o.Done():
+				return
+			case o.in <- x:
+				return
+			default:
+			}
+			select {
+			case <-o.in:
+				o.dropped.Add(1)
+			default:
+			}
+		}
+	case OverflowCancel:
+		select {
+		case <-o.Done():
+		case o.in <- x:
+		default:
+			o.dropped.Add(1)
+			o.CancelCause(ErrOverflow)
+		}
+	default:
+		select {
+		case <-o.Done():
+		case o.in <- x:
+		}
+	}
+}
+
+// emit delivers x to value directly. it is used by observers which are fed by
+// a single producer goroutine owning value channel.
+func (o *observer[T]) emit(x T) error {
 	select {
 	case <-o.Done():
-	case o.in <- x:
+		return o.Err()
+	case o.value <- x:
+		return nil
 	}
 }
 
+// Dropped returns the number of values discarded by overflow policy
+func (o *observer[T]) Dropped() uint64 {
+	return o.dropped.Load()
+}
+
 // Done returns completeness signal
 // copy from context/context.go:448
 func (o *observer[T]) Done() <-chan struct{} {
@@ -3,9 +3,11 @@ package chanx_test
 import (
 	"fmt"
 	"sync"
+	"testing"
 	"time"
 
 	"github.com/xoctopus/x/chanx"
+	. "github.com/xoctopus/x/testx"
 )
 
 func ExampleNotifiableObserver() {
@@ -40,3 +42,65 @@ func ExampleNotifiableObserver() {
 	// Received: 3
 	// Error: completed
 }
+
+func TestNewBufferedObserver(t *testing.T) {
+	fill := func(o chanx.BufferedObserver[int], n int) {
+		o.Send(1)
+		// wait for the forwarding goroutine to pick up the first value
+		time.Sleep(10 * time.Millisecond)
+		for i := 2; i <= n; i++ {
+			o.Send(i)
+		}
+	}
+	recv := func(o chanx.BufferedObserver[int], n int) []int {
+		values := make([]int, 0, n)
+		for range n {
+			values = append(values, <-o.Value())
+		}
+		return values
+	}
+
+	t.Run("Block", func(t *testing.T) {
+		o := chanx.NewBufferedObserver[int](2, chanx.OverflowBlock)
+		defer o.CancelCause(nil)
+
+		fill(o, 3)
+		Expect(t, recv(o, 3), Equal([]int{1, 2, 3}))
+		Expect(t, o.Dropped(), Equal[uint64](0))
+	})
+	t.Run("DropNewest", func(t *testing.T) {
+		o := chanx.NewBufferedObserver[int](2, chanx.OverflowDropNewest)
+		defer o.CancelCause(nil)
+
+		fill(o, 10)
+		Expect(t, recv(o, 3), Equal([]int{1, 2, 3}))
+		Expect(t, o.Dropped(), Equal[uint64](7))
+	})
+	t.Run("DropOldest", func(t *testing.T) {
+		o := chanx.NewBufferedObserver[int](2, chanx.OverflowDropOldest)
+		defer o.CancelCause(nil)
+
+		fill(o, 10)
+		Expect(t, recv(o, 3), Equal([]int{1, 9, 10}))
+		Expect(t, o.Dropped(), Equal[uint64](7))
+	})
+	t.Run("Cancel", func(t *testing.T) {
+		o := chanx.NewBufferedObserver[int](2, chanx.OverflowCancel)
+
+		fill(o, 4)
+		<-o.Done()
+		Expect(t, o.Err(), IsError(chanx.ErrOverflow))
+		Expect(t, o.Dropped(), Equal[uint64](1))
+
+		o.Send(5)
+		Expect(t, o.Dropped(), Equal[uint64](1))
+	})
+	t.Run("ZeroSize", func(t *testing.T) {
+		o := chanx.NewBufferedObserver[int](0, chanx.OverflowDropOldest)
+		defer o.CancelCause(nil)
+
+		fill(o, 3)
+		Expect(t, recv(o, 2), Equal([]int{1, 3}))
+		Expect(t, o.Dropped(), Equal[uint64](1))
+	})
+}
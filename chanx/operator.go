@@ -0,0 +1,323 @@
+package chanx
+
+import (
+	"errors"
+	"time"
+)
+
+// stream creates an Observer fed by run in a new goroutine. values are emitted
+// synchronously, so a consumer ranging Value receives every value before the
+// channel closes. the error returned by run completes the observer and cancels
+// all upstream observers.
+func stream[U any](run func(out *observer[U]) error, ins ...Cancelable) Observer[U] {
+	out := &observer[U]{value: make(chan U)}
+	go func() {
+		defer close(out.value)
+		out.CancelCause(run(out))
+		for _, in := range ins {
+			in.CancelCause(out.Err())
+		}
+	}()
+	return out
+}
+
+// derive creates an Observable subscribing src once per Observe
+func derive[T, U any](src Observable[T], run func(in Observer[T], out *observer[U]) error) Observable[U] {
+	return ObserverFunc[U](func() Observer[U] {
+		in := src.Observe()
+		return stream(func(out *observer[U]) error { return run(in, out) }, in)
+	})
+}
+
+// next receives a value from in. it returns in.Err() when in is completed and
+// out.Err() when out is canceled.
+func next[T, U any](in Observer[T], out *observer[U]) (T, error) {
+	select {
+	case <-out.Done():
+		return *new(T), out.Err()
+	case x, ok := <-in.Value():
+		if !ok {
+			return x, in.Err()
+		}
+		return x, nil
+	}
+}
+
+// pump forwards values from in to out until one of them is done
+func pump[T any](in Observer[T], out *observer[T]) error {
+	for {
+		x, err := next(in, out)
+		if err != nil {
+			return err
+		}
+		if err = out.emit(x); err != nil {
+			return err
+		}
+	}
+}
+
+// Of creates an Observable emitting values then completing
+func Of[T any](values ...T) Observable[T] {
+	return ObserverFunc[T](func() Observer[T] {
+		return stream(func(out *observer[T]) error {
+			for _, x := range values {
+				if err := out.emit(x); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// Map transforms each value by f
+func Map[T, U any](src Observable[T], f func(T) U) Observable[U] {
+	return derive(src, func(in Observer[T], out *observer[U]) error {
+		for {
+			x, err := next(in, out)
+			if err != nil {
+				return err
+			}
+			if err = out.emit(f(x)); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// Filter emits values satisfying f only
+func Filter[T any](src Observable[T], f func(T) bool) Observable[T] {
+	return derive(src, func(in Observer[T], out *observer[T]) error {
+		for {
+			x, err := next(in, out)
+			if err != nil {
+				return err
+			}
+			if !f(x) {
+				continue
+			}
+			if err = out.emit(x); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// Scan emits each intermediate result of accumulating values by f from seed
+func Scan[T, U any](src Observable[T], seed U, f func(U, T) U) Observable[U] {
+	return derive(src, func(in Observer[T], out *observer[U]) error {
+		acc := seed
+		for {
+			x, err := next(in, out)
+			if err != nil {
+				return err
+			}
+			acc = f(acc, x)
+			if err = out.emit(acc); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// Take emits the first n values then completes
+func Take[T any](src Observable[T], n int) Observable[T] {
+	return derive(src, func(in Observer[T], out *observer[T]) error {
+		for range n {
+			x, err := next(in, out)
+			if err != nil {
+				return err
+			}
+			if err = out.emit(x); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Skip discards the first n values
+func Skip[T any](src Observable[T], n int) Observable[T] {
+	return derive(src, func(in Observer[T], out *observer[T]) error {
+		for range n {
+			if _, err := next(in, out); err != nil {
+				return err
+			}
+		}
+		return pump(in, out)
+	})
+}
+
+// Merge emits values from all sources. it completes when all sources are
+// completed, or fails once any of them fails.
+func Merge[T any](srcs ...Observable[T]) Observable[T] {
+	return ObserverFunc[T](func() Observer[T] {
+		ins := make([]Cancelable, 0, len(srcs))
+		obs := make([]Observer[T], 0, len(srcs))
+		for _, src := range srcs {
+			in := src.Observe()
+			ins = append(ins, in)
+			obs = append(obs, in)
+		}
+		return stream(func(out *observer[T]) error {
+			errs := make(chan error, len(obs))
+			for _, in := range obs {
+				go func() { errs <- pump(in, out) }()
+			}
+			var cause error
+			for range obs {
+				err := <-errs
+				if cause == nil && !errors.Is(err, ErrCompleted) {
+					cause = err
+					for _, in := range ins {
+						in.CancelCause(err)
+					}
+				}
+			}
+			return cause
+		}, ins...)
+	})
+}
+
+// Zip combines values from a and b pairwise by f. it completes when either
+// source is completed.
+func Zip[A, B, U any](a Observable[A], b Observable[B], f func(A, B) U) Observable[U] {
+	return ObserverFunc[U](func() Observer[U] {
+		ina, inb := a.Observe(), b.Observe()
+		return stream(func(out *observer[U]) error {
+			for {
+				x, err := next(ina, out)
+				if err != nil {
+					return err
+				}
+				y, err := next(inb, out)
+				if err != nil {
+					return err
+				}
+				if err = out.emit(f(x, y)); err != nil {
+					return err
+				}
+			}
+		}, ina, inb)
+	})
+}
+
+// Debounce emits the latest value only after d has passed without another
+// value. the pending value is flushed when src is completed.
+func Debounce[T any](src Observable[T], d time.Duration) Observable[T] {
+	return derive(src, func(in Observer[T], out *observer[T]) error {
+		var (
+			timer   = time.NewTimer(d)
+			latest  T
+			pending bool
+		)
+		timer.Stop()
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-out.Done():
+				return out.Err()
+			case x, ok := <-in.Value():
+				if !ok {
+					if pending {
+						if err := out.emit(latest); err != nil {
+							return err
+						}
+					}
+					return in.Err()
+				}
+				latest, pending = x, true
+				timer.Reset(d)
+			case <-timer.C:
+				pending = false
+				if err := out.emit(latest); err != nil {
+					return err
+				}
+			}
+		}
+	})
+}
+
+// Throttle emits a value then ignores the following values for d
+func Throttle[T any](src Observable[T], d time.Duration) Observable[T] {
+	return derive(src, func(in Observer[T], out *observer[T]) error {
+		var last time.Time
+		for {
+			x, err := next(in, out)
+			if err != nil {
+				return err
+			}
+			if !last.IsZero() && time.Since(last) < d {
+				continue
+			}
+			last = time.Now()
+			if err = out.emit(x); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// BufferCount emits values in batches of n. the remaining values are flushed
+// when src is completed.
+func BufferCount[T any](src Observable[T], n int) Observable[[]T] {
+	n = max(n, 1)
+	return derive(src, func(in Observer[T], out *observer[[]T]) error {
+		batch := make([]T, 0, n)
+		for {
+			x, err := next(in, out)
+			if err != nil {
+				if len(batch) > 0 && out.Err() == nil {
+					if e := out.emit(batch); e != nil {
+						return e
+					}
+				}
+				return err
+			}
+			if batch = append(batch, x); len(batch) == n {
+				if err = out.emit(batch); err != nil {
+					return err
+				}
+				batch = make([]T, 0, n)
+			}
+		}
+	})
+}
+
+// BufferTime emits values collected in every period d. empty batches are
+// skipped and the remaining values are flushed when src is completed.
+func BufferTime[T any](src Observable[T], d time.Duration) Observable[[]T] {
+	return derive(src, func(in Observer[T], out *observer[[]T]) error {
+		var (
+			ticker = time.NewTicker(d)
+			batch  []T
+		)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-out.Done():
+				return out.Err()
+			case x, ok := <-in.Value():
+				if !ok {
+					if len(batch) > 0 {
+						if err := out.emit(batch); err != nil {
+							return err
+						}
+					}
+					return in.Err()
+				}
+				batch = append(batch, x)
+			case <-ticker.C:
+				if len(batch) == 0 {
+					continue
+				}
+				if err := out.emit(batch); err != nil {
+					return err
+				}
+				batch = nil
+			}
+		}
+	})
+}
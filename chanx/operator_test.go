@@ -0,0 +1,139 @@
+package chanx_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/xoctopus/x/chanx"
+	"github.com/xoctopus/x/iterx"
+	. "github.com/xoctopus/x/testx"
+)
+
+func collect[T any](o chanx.Observer[T]) []T {
+	return slices.Collect(iterx.Recv(o.Value()))
+}
+
+func ExampleMap() {
+	src := chanx.Of(1, 2, 3, 4, 5, 6)
+
+	evens := chanx.Filter(src, func(x int) bool { return x%2 == 0 })
+	sums := chanx.Scan(evens, 0, func(acc, x int) int { return acc + x })
+	texts := chanx.Map(sums, strconv.Itoa)
+
+	o := texts.Observe()
+	for v := range o.Value() {
+		fmt.Println(v)
+	}
+	fmt.Println(o.Err())
+
+	// Output:
+	// 2
+	// 6
+	// 12
+	// completed
+}
+
+func TestOperators(t *testing.T) {
+	t.Run("TakeSkip", func(t *testing.T) {
+		src := chanx.Of(1, 2, 3, 4, 5)
+		Expect(t, collect(chanx.Take(src, 2).Observe()), Equal([]int{1, 2}))
+		Expect(t, collect(chanx.Take(src, 0).Observe()), HaveLen[[]int](0))
+		Expect(t, collect(chanx.Skip(src, 3).Observe()), Equal([]int{4, 5}))
+		Expect(t, collect(chanx.Skip(src, 6).Observe()), HaveLen[[]int](0))
+	})
+	t.Run("Merge", func(t *testing.T) {
+		o := chanx.Merge(chanx.Of(1, 2), chanx.Of(3), chanx.Of(4, 5)).Observe()
+		Expect(t, slices.Sorted(iterx.Recv(o.Value())), Equal([]int{1, 2, 3, 4, 5}))
+		Expect(t, o.Err(), IsError(chanx.ErrCompleted))
+
+		t.Run("Failed", func(t *testing.T) {
+			cause := errors.New("failed")
+			s := &chanx.Subject[int]{}
+			o := chanx.Merge[int](s, chanx.Of(1)).Observe()
+			Expect(t, <-o.Value(), Equal(1))
+			s.CancelCause(cause)
+			_ = collect(o)
+			Expect(t, o.Err(), IsError(cause))
+		})
+		t.Run("Empty", func(t *testing.T) {
+			o := chanx.Merge[int]().Observe()
+			Expect(t, collect(o), HaveLen[[]int](0))
+			Expect(t, o.Err(), IsError(chanx.ErrCompleted))
+		})
+	})
+	t.Run("Zip", func(t *testing.T) {
+		o := chanx.Zip(
+			chanx.Of(1, 2, 3),
+			chanx.Of("a", "b"),
+			func(x int, y string) string { return strconv.Itoa(x) + y },
+		).Observe()
+		Expect(t, collect(o), Equal([]string{"1a", "2b"}))
+	})
+	t.Run("Debounce", func(t *testing.T) {
+		s := &chanx.Subject[int]{}
+		o := chanx.Debounce[int](s, 20*time.Millisecond).Observe()
+		go func() {
+			s.Send(1)
+			s.Send(2)
+			time.Sleep(50 * time.Millisecond)
+			s.Send(3)
+			s.Send(4)
+			time.Sleep(50 * time.Millisecond)
+			s.Send(5)
+			time.Sleep(10 * time.Millisecond)
+			s.CancelCause(nil)
+		}()
+		Expect(t, collect(o), Equal([]int{2, 4, 5}))
+		Expect(t, o.Err(), IsError(chanx.ErrCompleted))
+	})
+	t.Run("Throttle", func(t *testing.T) {
+		s := &chanx.Subject[int]{}
+		o := chanx.Throttle[int](s, 30*time.Millisecond).Observe()
+		go func() {
+			s.Send(1)
+			s.Send(2)
+			time.Sleep(50 * time.Millisecond)
+			s.Send(3)
+			s.Send(4)
+			time.Sleep(10 * time.Millisecond)
+			s.CancelCause(nil)
+		}()
+		Expect(t, collect(o), Equal([]int{1, 3}))
+	})
+	t.Run("BufferCount", func(t *testing.T) {
+		o := chanx.BufferCount(chanx.Of(1, 2, 3, 4, 5), 2).Observe()
+		Expect(t, collect(o), Equal([][]int{{1, 2}, {3, 4}, {5}}))
+	})
+	t.Run("BufferTime", func(t *testing.T) {
+		s := &chanx.Subject[int]{}
+		o := chanx.BufferTime[int](s, 20*time.Millisecond).Observe()
+		go func() {
+			s.Send(1)
+			s.Send(2)
+			time.Sleep(50 * time.Millisecond)
+			s.Send(3)
+			time.Sleep(10 * time.Millisecond)
+			s.CancelCause(nil)
+		}()
+		Expect(t, collect(o), Equal([][]int{{1, 2}, {3}}))
+	})
+	t.Run("CancelPropagation", func(t *testing.T) {
+		cause := errors.New("canceled")
+		s := &chanx.Subject[int]{}
+		upstream := s.Observe()
+		o := chanx.Map(
+			chanx.ObserverFunc[int](func() chanx.Observer[int] { return upstream }),
+			strconv.Itoa,
+		).Observe()
+
+		s.Send(1)
+		Expect(t, <-o.Value(), Equal("1"))
+		o.CancelCause(cause)
+		<-upstream.Done()
+		Expect(t, upstream.Err(), IsError(cause))
+	})
+}
@@ -0,0 +1,175 @@
+package chanx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewBehaviorSubject creates a BehaviorSubject holding v as its current value
+func NewBehaviorSubject[T any](v T, options ...SubjectOption[T]) *BehaviorSubject[T] {
+	s := &BehaviorSubject[T]{latest: v, has: true}
+	s.apply(options...)
+	return s
+}
+
+// BehaviorSubject is a Subject which replays the latest sent value to each new
+// subscriber. a zero BehaviorSubject has no value until the first Send.
+type BehaviorSubject[T any] struct {
+	Subject[T]
+
+	// send serializes sending and subscribing, so that a new subscriber
+	// receives the latest value before any later one
+	send sync.Mutex
+	// mu guards latest only, so that subscribers can read Value while handling
+	mu     sync.Mutex
+	latest T
+	has    bool
+}
+
+// Value returns the latest sent value
+func (s *BehaviorSubject[T]) Value() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest, s.has
+}
+
+func (s *BehaviorSubject[T]) Send(x T) {
+	s.send.Lock()
+	defer s.send.Unlock()
+
+	if s.Err() != nil {
+		return
+	}
+	s.mu.Lock()
+	s.latest, s.has = x, true
+	s.mu.Unlock()
+
+	s.Subject.Send(x)
+}
+
+func (s *BehaviorSubject[T]) Observe() Observer[T] {
+	return s.ObserveContext(context.Background())
+}
+
+func (s *BehaviorSubject[T]) ObserveContext(ctx context.Context) Observer[T] {
+	o := NewBufferedObserver[T](1, OverflowBlock)
+	s.SubscribeContext(ctx, o)
+	return o
+}
+
+// Subscribe registers o and sends it the latest value before values sent
+// later. the latest value is queued, so Subscribe does not wait for o to
+// receive it, and o is delivered asynchronously as WithAsync does.
+func (s *BehaviorSubject[T]) Subscribe(o Subscriber[T]) {
+	s.SubscribeContext(context.Background(), o)
+}
+
+func (s *BehaviorSubject[T]) SubscribeContext(ctx context.Context, o Subscriber[T]) {
+	s.send.Lock()
+	defer s.send.Unlock()
+
+	var queued []T
+	if v, ok := s.Value(); ok {
+		queued = append(queued, v)
+	}
+	s.subscribe(ctx, o, queued)
+}
+
+// NewReplaySubject creates a ReplaySubject keeping at most size values which
+// are sent within window. a non-positive size or window means no limitation.
+func NewReplaySubject[T any](size int, window time.Duration, options ...SubjectOption[T]) *ReplaySubject[T] {
+	s := &ReplaySubject[T]{size: size, window: window}
+	s.apply(options...)
+	return s
+}
+
+// ReplaySubject is a Subject which replays the history of sent values to each
+// new subscriber. a zero ReplaySubject keeps all values.
+type ReplaySubject[T any] struct {
+	Subject[T]
+
+	// send serializes sending and subscribing, so that a new subscriber
+	// receives the history before any later value
+	send sync.Mutex
+	// mu guards history only, so that subscribers can read Values while
+	// handling
+	mu      sync.Mutex
+	size    int
+	window  time.Duration
+	history []replayed[T]
+}
+
+type replayed[T any] struct {
+	at time.Time
+	v  T
+}
+
+// Values returns the replayable values
+func (s *ReplaySubject[T]) Values() []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.trim()
+	values := make([]T, 0, len(s.history))
+	for _, r := range s.history {
+		values = append(values, r.v)
+	}
+	return values
+}
+
+func (s *ReplaySubject[T]) Send(x T) {
+	s.send.Lock()
+	defer s.send.Unlock()
+
+	if s.Err() != nil {
+		return
+	}
+	s.mu.Lock()
+	s.history = append(s.history, replayed[T]{at: time.Now(), v: x})
+	s.trim()
+	s.mu.Unlock()
+
+	s.Subject.Send(x)
+}
+
+func (s *ReplaySubject[T]) Observe() Observer[T] {
+	return s.ObserveContext(context.Background())
+}
+
+func (s *ReplaySubject[T]) ObserveContext(ctx context.Context) Observer[T] {
+	o := NewNotifiableObserver[T]()
+	s.SubscribeContext(ctx, o)
+	return o
+}
+
+// Subscribe registers o and sends it the history values in order before values
+// sent later. the history is queued, so Subscribe does not wait for o to
+// receive it, and o is delivered asynchronously as WithAsync does.
+func (s *ReplaySubject[T]) Subscribe(o Subscriber[T]) {
+	s.SubscribeContext(context.Background(), o)
+}
+
+func (s *ReplaySubject[T]) SubscribeContext(ctx context.Context, o Subscriber[T]) {
+	s.send.Lock()
+	defer s.send.Unlock()
+
+	s.subscribe(ctx, o, s.Values())
+}
+
+// trim drops expired and overflowed history values
+func (s *ReplaySubject[T]) trim() {
+	i := 0
+	if s.size > 0 && len(s.history) > s.size {
+		i = len(s.history) - s.size
+	}
+	if s.window > 0 {
+		deadline := time.Now().Add(-s.window)
+		for i < len(s.history) && s.history[i].at.Before(deadline) {
+			i++
+		}
+	}
+	if i > 0 {
+		s.history = append(s.history[:0:0], s.history[i:]...)
+	}
+}
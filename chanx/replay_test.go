@@ -0,0 +1,179 @@
+package chanx_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xoctopus/x/chanx"
+	. "github.com/xoctopus/x/testx"
+)
+
+func ExampleBehaviorSubject() {
+	subject := chanx.NewBehaviorSubject("v1")
+
+	o1 := subject.Observe()
+	fmt.Println("o1 received:", <-o1.Value())
+
+	subject.Send("v2")
+	fmt.Println("o1 received:", <-o1.Value())
+
+	// late subscriber receives the current snapshot
+	o2 := subject.Observe()
+	fmt.Println("o2 received:", <-o2.Value())
+
+	subject.CancelCause(nil)
+
+	// Output:
+	// o1 received: v1
+	// o1 received: v2
+	// o2 received: v2
+}
+
+func TestBehaviorSubject(t *testing.T) {
+	s := &chanx.BehaviorSubject[int]{}
+	_, ok := s.Value()
+	Expect(t, ok, BeFalse())
+
+	o1 := s.Observe()
+	s.Send(1)
+	Expect(t, <-o1.Value(), Equal(1))
+
+	v, ok := s.Value()
+	Expect(t, ok, BeTrue())
+	Expect(t, v, Equal(1))
+
+	o2 := s.Observe()
+	Expect(t, <-o2.Value(), Equal(1))
+
+	s.CancelCause(nil)
+	s.Send(2)
+	v, _ = s.Value()
+	Expect(t, v, Equal(1))
+
+	o3 := s.Observe()
+	<-o3.Done()
+	Expect(t, o3.Err(), IsError(chanx.ErrCompleted))
+}
+
+func TestBehaviorSubjectSubscribeUnbuffered(t *testing.T) {
+	s := chanx.NewBehaviorSubject(1)
+	o := chanx.NewNotifiableObserver[int]()
+	s.Subscribe(o)
+	s.Send(2)
+	s.Send(3)
+
+	for _, v := range []int{1, 2, 3} {
+		Expect(t, <-o.Value(), Equal(v))
+	}
+	s.CancelCause(nil)
+}
+
+func TestBehaviorSubjectReadWhileHandling(t *testing.T) {
+	s := chanx.NewBehaviorSubject(0)
+	o := s.Observe()
+
+	go func() {
+		for i := range 10 {
+			s.Send(i + 1)
+		}
+	}()
+
+	received := 0
+	for x := range o.Value() {
+		v, ok := s.Value()
+		Expect(t, ok, BeTrue())
+		Expect(t, v >= x, BeTrue())
+		if received++; x == 10 {
+			break
+		}
+	}
+	Expect(t, received, Equal(11))
+	s.CancelCause(nil)
+}
+
+func TestReplaySubject(t *testing.T) {
+	t.Run("Unbounded", func(t *testing.T) {
+		s := &chanx.ReplaySubject[int]{}
+		s.Send(1)
+		s.Send(2)
+		s.Send(3)
+
+		o := s.Observe()
+		Expect(t, <-o.Value(), Equal(1))
+		Expect(t, <-o.Value(), Equal(2))
+		Expect(t, <-o.Value(), Equal(3))
+
+		s.Send(4)
+		Expect(t, <-o.Value(), Equal(4))
+		s.CancelCause(nil)
+	})
+	t.Run("BoundedBySize", func(t *testing.T) {
+		s := chanx.NewReplaySubject[int](2, 0)
+		for i := range 5 {
+			s.Send(i)
+		}
+		Expect(t, s.Values(), Equal([]int{3, 4}))
+
+		o := s.Observe()
+		Expect(t, <-o.Value(), Equal(3))
+		Expect(t, <-o.Value(), Equal(4))
+		s.CancelCause(nil)
+	})
+	t.Run("BoundedByWindow", func(t *testing.T) {
+		s := chanx.NewReplaySubject[int](0, 30*time.Millisecond)
+		s.Send(1)
+		time.Sleep(50 * time.Millisecond)
+		s.Send(2)
+		Expect(t, s.Values(), Equal([]int{2}))
+
+		time.Sleep(50 * time.Millisecond)
+		Expect(t, s.Values(), HaveLen[[]int](0))
+	})
+	t.Run("ReadWhileHandling", func(t *testing.T) {
+		s := chanx.NewReplaySubject[int](0, 0)
+		o := s.Observe()
+
+		go func() {
+			for i := range 10 {
+				s.Send(i)
+			}
+		}()
+
+		for x := range o.Value() {
+			Expect(t, len(s.Values()) > x, BeTrue())
+			if x == 9 {
+				break
+			}
+		}
+		Expect(t, s.Values(), HaveLen[[]int](10))
+		s.CancelCause(nil)
+	})
+	t.Run("Subscribe", func(t *testing.T) {
+		s := chanx.NewReplaySubject[int](3, time.Minute)
+		s.Send(1)
+		s.Send(2)
+
+		o := chanx.NewNotifiableObserver[int]()
+		subscribed := make(chan struct{})
+		go func() {
+			s.Subscribe(o)
+			close(subscribed)
+		}()
+		select {
+		case <-subscribed:
+		case <-time.After(time.Second):
+			t.Fatal("Subscribe blocked on replaying history")
+		}
+
+		s.Send(3)
+		s.Send(4)
+		for _, v := range []int{1, 2, 3, 4} {
+			Expect(t, <-o.Value(), Equal(v))
+		}
+
+		s.CancelCause(nil)
+		<-o.Done()
+		Expect(t, o.Err(), IsError(chanx.ErrCompleted))
+	})
+}
@@ -0,0 +1,76 @@
+package chanx
+
+import (
+	"context"
+)
+
+// Envelope carries a request or reply value correlated by ID
+type Envelope[ID comparable, T any] struct {
+	ID    ID
+	Value T
+}
+
+// Exchange implements request/reply pattern over a pair of subjects. requests
+// are broadcast to all responders and replies are correlated by envelope ID. a
+// zero Exchange is ready to use.
+type Exchange[ID comparable, Req, Rep any] struct {
+	Requests Subject[Envelope[ID, Req]]
+	Replies  Subject[Envelope[ID, Rep]]
+}
+
+// Request sends req with id and waits for the first reply. it returns
+// context.Cause(ctx) when ctx is done before any reply.
+func (e *Exchange[ID, Req, Rep]) Request(ctx context.Context, id ID, req Req) (Rep, error) {
+	replies, err := e.Gather(ctx, id, req, 1)
+	if err != nil {
+		return *new(Rep), err
+	}
+	return replies[0], nil
+}
+
+// Gather sends req with id and collects n replies (scatter-gather). it returns
+// the collected replies with context.Cause(ctx) when ctx is done before n
+// replies arrive. a non-positive n collects replies until ctx is done without
+// reporting the cause.
+// the request is sent in a new goroutine which is not bound to ctx, because
+// Send of Subject cannot be abandoned. if a responder is not receiving, the
+// goroutine outlives Gather until the request is delivered to all responders
+// or Requests is canceled.
+func (e *Exchange[ID, Req, Rep]) Gather(ctx context.Context, id ID, req Req, n int) ([]Rep, error) {
+	// observe before requesting to avoid missing any reply
+	o := e.Replies.ObserveContext(ctx)
+	defer o.CancelCause(nil)
+
+	// sending asynchronously to keep receiving replies while responders are
+	// blocked on delivering replies of other requests
+	go e.Requests.Send(Envelope[ID, Req]{ID: id, Value: req})
+
+	replies := make([]Rep, 0, max(n, 0))
+	for rep := range Seq(o) {
+		if rep.ID != id {
+			continue
+		}
+		if replies = append(replies, rep.Value); len(replies) == n {
+			return replies, nil
+		}
+	}
+
+	err := o.Err()
+	if n <= 0 && ctx.Err() != nil {
+		err = nil
+	}
+	return replies, err
+}
+
+// Serve handles requests by handler and replies with the same id until ctx is
+// done or Requests is completed. it returns the cause of ending.
+// handler runs inline in the serving goroutine, so a slow handler delays the
+// replies of all following requests; callers can run multiple Serve or handle
+// requests concurrently in handler to avoid it.
+func (e *Exchange[ID, Req, Rep]) Serve(ctx context.Context, handler func(context.Context, Req) Rep) error {
+	o := e.Requests.ObserveContext(ctx)
+	for req := range Seq(o) {
+		e.Replies.Send(Envelope[ID, Rep]{ID: req.ID, Value: handler(ctx, req.Value)})
+	}
+	return o.Err()
+}
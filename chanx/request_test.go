@@ -0,0 +1,146 @@
+package chanx_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xoctopus/x/chanx"
+	. "github.com/xoctopus/x/testx"
+)
+
+func ExampleExchange() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := &chanx.Exchange[int, int, string]{}
+	go func() {
+		_ = e.Serve(ctx, func(_ context.Context, x int) string {
+			return strconv.Itoa(x * x)
+		})
+	}()
+	time.Sleep(10 * time.Millisecond) // wait responder ready
+
+	rep, err := e.Request(ctx, 1, 3)
+	fmt.Println(rep, err)
+
+	// Output:
+	// 9 <nil>
+}
+
+func TestExchange(t *testing.T) {
+	serve := func(ctx context.Context, e *chanx.Exchange[int, int, string], name string) {
+		go func() {
+			_ = e.Serve(ctx, func(_ context.Context, x int) string {
+				return name + ":" + strconv.Itoa(x)
+			})
+		}()
+	}
+
+	t.Run("Timeout", func(t *testing.T) {
+		e := &chanx.Exchange[int, int, string]{}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := e.Request(ctx, 1, 1)
+		Expect(t, err, IsError(context.DeadlineExceeded))
+	})
+	t.Run("Gather", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		e := &chanx.Exchange[int, int, string]{}
+		serve(ctx, e, "a")
+		serve(ctx, e, "b")
+		serve(ctx, e, "c")
+		time.Sleep(10 * time.Millisecond)
+
+		replies, err := e.Gather(ctx, 1, 1, 3)
+		Expect(t, err, Succeed())
+		Expect(t, slices.Sorted(slices.Values(replies)), Equal([]string{"a:1", "b:1", "c:1"}))
+
+		t.Run("Partial", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+			defer cancel()
+
+			replies, err := e.Gather(ctx, 2, 2, 4)
+			Expect(t, err, IsError(context.DeadlineExceeded))
+			Expect(t, replies, HaveLen[[]string](3))
+		})
+		t.Run("UntilTimeout", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+			defer cancel()
+
+			replies, err := e.Gather(ctx, 3, 3, 0)
+			Expect(t, err, Succeed())
+			Expect(t, replies, HaveLen[[]string](3))
+		})
+	})
+	t.Run("Correlation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		e := &chanx.Exchange[int, int, string]{}
+		serve(ctx, e, "a")
+		time.Sleep(10 * time.Millisecond)
+
+		results := make(chan string, 10)
+		for i := range 10 {
+			go func() {
+				rep, err := e.Request(ctx, i, i)
+				Expect(t, err, Succeed())
+				results <- rep
+			}()
+		}
+		for range 10 {
+			rep := <-results
+			Expect(t, rep, HavePrefix("a:"))
+		}
+	})
+	t.Run("PendingSend", func(t *testing.T) {
+		e := &chanx.Exchange[int, int, string]{}
+		// a responder never receiving blocks the request sending
+		r := chanx.NewNotifiableObserver[chanx.Envelope[int, int]]()
+		e.Requests.Subscribe(r)
+		defer r.CancelCause(nil)
+
+		for id := range 2 {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			_, err := e.Request(ctx, id, id)
+			cancel()
+			Expect(t, err, IsError(context.DeadlineExceeded))
+		}
+
+		// the sending goroutine outlives Request until the request is received
+		Expect(t, sending(), BeTrue())
+		Expect(t, (<-r.Value()).ID, Equal(0))
+		Expect(t, (<-r.Value()).ID, Equal(1))
+		deadline := time.Now().Add(time.Second)
+		for sending() && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		Expect(t, sending(), BeFalse())
+	})
+	t.Run("Completed", func(t *testing.T) {
+		e := &chanx.Exchange[int, int, string]{}
+		e.Replies.CancelCause(nil)
+
+		_, err := e.Request(context.Background(), 1, 1)
+		Expect(t, err, IsError(chanx.ErrCompleted))
+
+		e.Requests.CancelCause(nil)
+		Expect(t, e.Serve(context.Background(), nil), IsError(chanx.ErrCompleted))
+	})
+}
+
+// sending reports if any goroutine created by Gather is sending request
+func sending() bool {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	return strings.Contains(string(buf), "created by github.com/xoctopus/x/chanx.(*Exchange[...]).Gather")
+}
@@ -0,0 +1,73 @@
+package chanx
+
+import (
+	"context"
+	"iter"
+)
+
+// Seq converts o to an iter.Seq ending on o is done, then o.Err() reports the
+// cause. breaking the iteration early completes o to release its subscription.
+func Seq[T any](o Observer[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case <-o.Done():
+				return
+			case x, ok := <-o.Value():
+				if !ok {
+					return
+				}
+				if !yield(x) {
+					o.CancelCause(nil)
+					return
+				}
+			}
+		}
+	}
+}
+
+// FromSeq creates an Observable iterating seq for each observer. the observer
+// is completed when seq is exhausted, or canceled by context.Cause(ctx) when ctx
+// is done. FromSeq is the cold side of bridging iter.Seq into chanx, each
+// observer iterates seq by itself; to drive a Subject shared by subscribers
+// with seq, use Publish.
+func FromSeq[T any](ctx context.Context, seq iter.Seq[T]) Observable[T] {
+	return ObserverFunc[T](func() Observer[T] {
+		return stream(func(out *observer[T]) error {
+			// cancels out to stop emitting when ctx is done
+			stop := context.AfterFunc(ctx, func() {
+				out.CancelCause(context.Cause(ctx))
+			})
+			defer stop()
+
+			for x := range seq {
+				if ctx.Err() != nil {
+					return context.Cause(ctx)
+				}
+				if err := out.emit(x); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// Publish sends values of seq to s until seq is exhausted or ctx is done, then
+// completes s with nil or context.Cause(ctx). it blocks until publishing ends.
+// it is the hot side of bridging iter.Seq into chanx, values are broadcast to
+// subscribers of s as they are produced by seq.
+func Publish[T any](ctx context.Context, s Subscriber[T], seq iter.Seq[T]) {
+	for x := range seq {
+		select {
+		case <-ctx.Done():
+			s.CancelCause(context.Cause(ctx))
+			return
+		case <-s.Done():
+			return
+		default:
+		}
+		s.Send(x)
+	}
+	s.CancelCause(nil)
+}
@@ -0,0 +1,126 @@
+package chanx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/xoctopus/x/chanx"
+	"github.com/xoctopus/x/iterx"
+	. "github.com/xoctopus/x/testx"
+)
+
+func ExampleSeq() {
+	src := chanx.FromSeq(context.Background(), iterx.Of([]int{1, 2, 3, 4}))
+	o := chanx.Map(src, func(x int) int { return x * x }).Observe()
+
+	for v := range chanx.Seq(o) {
+		fmt.Println(v)
+	}
+	fmt.Println(o.Err())
+
+	// Output:
+	// 1
+	// 4
+	// 9
+	// 16
+	// completed
+}
+
+func ExamplePublish() {
+	s := chanx.NewReplaySubject[int](0, 0)
+	chanx.Publish(context.Background(), s, iterx.Of([]int{1, 2, 3}))
+	fmt.Println(s.Values(), s.Err())
+
+	// Output:
+	// [1 2 3] completed
+}
+
+func TestSeq(t *testing.T) {
+	t.Run("Break", func(t *testing.T) {
+		s := &chanx.Subject[int]{}
+		o := s.Observe()
+		go s.Send(1)
+
+		for range chanx.Seq(o) {
+			break
+		}
+		<-o.Done()
+		Expect(t, o.Err(), IsError(chanx.ErrCompleted))
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		cause := errors.New("canceled")
+		o := chanx.NewNotifiableObserver[int]()
+		o.CancelCause(cause)
+
+		Expect(t, slices.Collect(chanx.Seq[int](o)), HaveLen[[]int](0))
+		Expect(t, o.Err(), IsError(cause))
+	})
+}
+
+func TestFromSeq(t *testing.T) {
+	t.Run("ContextCanceled", func(t *testing.T) {
+		cause := errors.New("canceled")
+		ctx, cancel := context.WithCancelCause(context.Background())
+
+		o := chanx.FromSeq(ctx, iterx.Of([]int{1, 2, 3})).Observe()
+		Expect(t, <-o.Value(), Equal(1))
+		cancel(cause)
+
+		_ = slices.Collect(chanx.Seq(o))
+		Expect(t, o.Err(), IsError(cause))
+	})
+	t.Run("ContextCanceledWhileEmitting", func(t *testing.T) {
+		cause := errors.New("canceled")
+		ctx, cancel := context.WithCancelCause(context.Background())
+
+		o := chanx.FromSeq(ctx, iterx.Of([]int{1, 2, 3})).Observe()
+		cancel(cause)
+
+		<-o.Done()
+		Expect(t, o.Err(), IsError(cause))
+	})
+	t.Run("ObserverCanceled", func(t *testing.T) {
+		cause := errors.New("canceled")
+		o := chanx.FromSeq(context.Background(), iterx.Of([]int{1, 2, 3})).Observe()
+		Expect(t, <-o.Value(), Equal(1))
+		o.CancelCause(cause)
+
+		_ = slices.Collect(chanx.Seq(o))
+		Expect(t, o.Err(), IsError(cause))
+	})
+}
+
+func TestPublish(t *testing.T) {
+	t.Run("Completed", func(t *testing.T) {
+		s := chanx.NewReplaySubject[int](0, 0)
+		chanx.Publish(context.Background(), s, iterx.Of([]int{1, 2, 3}))
+
+		Expect(t, s.Values(), Equal([]int{1, 2, 3}))
+		Expect(t, s.Err(), IsError(chanx.ErrCompleted))
+	})
+	t.Run("ContextCanceled", func(t *testing.T) {
+		cause := errors.New("canceled")
+		ctx, cancel := context.WithCancelCause(context.Background())
+		s := chanx.NewReplaySubject[int](0, 0)
+
+		chanx.Publish(ctx, s, func(yield func(int) bool) {
+			for i := 1; yield(i); i++ {
+				if i == 2 {
+					cancel(cause)
+				}
+			}
+		})
+		Expect(t, s.Values(), Equal([]int{1, 2}))
+		Expect(t, s.Err(), IsError(cause))
+	})
+	t.Run("SubjectCanceled", func(t *testing.T) {
+		s := chanx.NewReplaySubject[int](0, 0)
+		s.CancelCause(nil)
+
+		chanx.Publish(context.Background(), s, iterx.Of([]int{1, 2, 3}))
+		Expect(t, s.Values(), HaveLen[[]int](0))
+	})
+}
@@ -0,0 +1,77 @@
+package chanx
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of Subject statistics
+type Stats struct {
+	// Subscribers is the number of current subscribers
+	Subscribers int
+	// Sent is the number of values sent to subject
+	Sent uint64
+	// Delivered is the number of values accepted by subscribers
+	Delivered uint64
+	// Dropped is the number of values discarded by overflowed, canceled or
+	// panicking subscribers
+	Dropped uint64
+	// Slowest is the longest latency of delivering a value to a subscriber
+	Slowest time.Duration
+}
+
+// Delivery describes a value delivered to a subscriber
+type Delivery[T any] struct {
+	Subscriber Subscriber[T]
+	Latency    time.Duration
+	Dropped    bool
+}
+
+// WithDeliveryHook notifies hook after each delivery. it is called in the
+// sending goroutine, or concurrently in the delivering goroutines of
+// subscribers under WithAsync, so hook must be safe for concurrent use and
+// should return quickly.
+func WithDeliveryHook[T any](hook func(Delivery[T])) SubjectOption[T] {
+	return func(s *Subject[T]) {
+		s.onDeliver = hook
+	}
+}
+
+type counters struct {
+	sent      atomic.Uint64
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	slowest   atomic.Int64
+}
+
+// Stats returns a snapshot of statistics
+func (s *Subject[T]) Stats() Stats {
+	s.mu.Lock()
+	subscribers := len(s.subs)
+	s.mu.Unlock()
+
+	return Stats{
+		Subscribers: subscribers,
+		Sent:        s.stats.sent.Load(),
+		Delivered:   s.stats.delivered.Load(),
+		Dropped:     s.stats.dropped.Load(),
+		Slowest:     time.Duration(s.stats.slowest.Load()),
+	}
+}
+
+func (s *Subject[T]) record(o Subscriber[T], latency time.Duration, dropped bool) {
+	if dropped {
+		s.stats.dropped.Add(1)
+	} else {
+		s.stats.delivered.Add(1)
+	}
+	for {
+		slowest := s.stats.slowest.Load()
+		if int64(latency) <= slowest || s.stats.slowest.CompareAndSwap(slowest, int64(latency)) {
+			break
+		}
+	}
+	if s.onDeliver != nil {
+		s.onDeliver(Delivery[T]{Subscriber: o, Latency: latency, Dropped: dropped})
+	}
+}
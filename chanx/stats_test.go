@@ -0,0 +1,68 @@
+package chanx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xoctopus/x/chanx"
+	. "github.com/xoctopus/x/testx"
+)
+
+type slowSubscriber struct {
+	chanx.NotifiableObserver[int]
+}
+
+func (s *slowSubscriber) Send(x int) {
+	time.Sleep(20 * time.Millisecond)
+	s.NotifiableObserver.Send(x)
+}
+
+func TestSubject_Stats(t *testing.T) {
+	var (
+		deliveries = make(chan chanx.Delivery[int], 16)
+		s          = chanx.NewSubject(chanx.WithDeliveryHook(
+			func(d chanx.Delivery[int]) { deliveries <- d },
+		))
+	)
+	Expect(t, s.Stats(), Equal(chanx.Stats{}))
+
+	fast := chanx.NewBufferedObserver[int](1, chanx.OverflowDropNewest)
+	slow := &slowSubscriber{chanx.NewBufferedObserver[int](2, chanx.OverflowBlock)}
+	s.Subscribe(fast)
+	s.Subscribe(slow)
+
+	s.Send(1)
+	s.Send(2)
+	time.Sleep(10 * time.Millisecond)
+	s.Send(3)
+
+	stats := s.Stats()
+	Expect(t, stats.Subscribers, Equal(2))
+	Expect(t, stats.Sent, Equal[uint64](3))
+	Expect(t, stats.Delivered, Equal[uint64](5))
+	Expect(t, stats.Dropped, Equal[uint64](1))
+	Expect(t, stats.Slowest, BeGte(20*time.Millisecond))
+
+	dropped := 0
+	for range 6 {
+		d := <-deliveries
+		if d.Dropped {
+			dropped++
+			Expect(t, d.Subscriber, Equal[chanx.Subscriber[int]](fast))
+		}
+		if d.Subscriber == chanx.Subscriber[int](slow) {
+			Expect(t, d.Latency, BeGte(20*time.Millisecond))
+		}
+	}
+	Expect(t, dropped, Equal(1))
+
+	fast.CancelCause(nil)
+	time.Sleep(10 * time.Millisecond)
+	Expect(t, s.Stats().Subscribers, Equal(1))
+
+	s.CancelCause(nil)
+	s.Send(4)
+	stats = s.Stats()
+	Expect(t, stats.Subscribers, Equal(0))
+	Expect(t, stats.Sent, Equal[uint64](3))
+}
@@ -1,15 +1,55 @@
 package chanx
 
 import (
+	"context"
+	"maps"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
+type SubjectOption[T any] func(*Subject[T])
+
+// WithRecover isolates panics raised by subscribers. the panicking subscriber
+// is canceled and unsubscribed with a *PanicError, then hook is notified if it
+// is not nil.
+func WithRecover[T any](hook func(Subscriber[T], *PanicError)) SubjectOption[T] {
+	return func(s *Subject[T]) {
+		s.recover = true
+		s.onPanic = hook
+	}
+}
+
+// NewSubject creates a Subject with options. a zero Subject is ready to use
+// without any option.
+func NewSubject[T any](options ...SubjectOption[T]) *Subject[T] {
+	s := &Subject[T]{}
+	s.apply(options...)
+	return s
+}
+
 type Subject[T any] struct {
 	mu   sync.Mutex
 	done atomic.Value
 	err  error
-	subs map[Subscriber[T]]struct{}
+	subs map[Subscriber[T]]*mailbox[T]
+
+	recover bool
+	onPanic func(Subscriber[T], *PanicError)
+
+	stats     counters
+	onDeliver func(Delivery[T])
+
+	async bool
+}
+
+func (s *Subject[T]) apply(options ...SubjectOption[T]) {
+	for _, option := range options {
+		if option != nil {
+			option(s)
+		}
+	}
 }
 
 func (s *Subject[T]) Err() error {
@@ -68,24 +108,92 @@ func (s *Subject[T]) Send(x T) {
 		return // already canceled
 	}
 
-	subs := make([]Subscriber[T], 0, len(s.subs))
-	for ob := range s.subs {
-		subs = append(subs, ob)
+	subs := maps.Clone(s.subs)
+	s.mu.Unlock()
+
+	s.stats.sent.Add(1)
+
+	for ob, mb := range subs {
+		if mb == nil {
+			s.deliver(ob, x)
+			continue
+		}
+		if !mb.push(x) {
+			s.record(ob, 0, true)
+		}
+	}
+}
+
+func (s *Subject[T]) deliver(o Subscriber[T], x T) {
+	var (
+		start   = time.Now()
+		counter interface{ Dropped() uint64 }
+		before  uint64
+		dropped bool
+	)
+	if counter, _ = o.(interface{ Dropped() uint64 }); counter != nil {
+		before = counter.Dropped()
 	}
+
+	defer func() {
+		if s.recover {
+			if r := recover(); r != nil {
+				dropped = true
+				s.recovered(o, r)
+			}
+		}
+		if !dropped {
+			dropped = o.Err() != nil || counter != nil && counter.Dropped() > before
+		}
+		s.record(o, time.Since(start), dropped)
+	}()
+
+	o.Send(x)
+}
+
+func (s *Subject[T]) recovered(o Subscriber[T], r any) {
+	err := &PanicError{Value: r, Stack: debug.Stack()}
+
+	s.mu.Lock()
+	delete(s.subs, o)
 	s.mu.Unlock()
 
-	for _, ob := range subs {
-		ob.Send(x)
+	func() {
+		defer func() { _ = recover() }()
+		o.CancelCause(err)
+	}()
+	if s.onPanic != nil {
+		s.onPanic(o, err)
 	}
 }
 
 func (s *Subject[T]) Observe() Observer[T] {
+	return s.ObserveContext(context.Background())
+}
+
+// ObserveContext likes Observe, but the observer is canceled by
+// context.Cause(ctx) when ctx is done.
+func (s *Subject[T]) ObserveContext(ctx context.Context) Observer[T] {
 	o := NewNotifiableObserver[T]()
-	s.Subscribe(o)
+	s.SubscribeContext(ctx, o)
 	return o
 }
 
 func (s *Subject[T]) Subscribe(o Subscriber[T]) {
+	s.SubscribeContext(context.Background(), o)
+}
+
+// SubscribeContext likes Subscribe, but o is canceled by context.Cause(ctx)
+// and unsubscribed when ctx is done. subscribing o again before it is
+// unsubscribed is ignored.
+func (s *Subject[T]) SubscribeContext(ctx context.Context, o Subscriber[T]) {
+	s.subscribe(ctx, o, nil)
+}
+
+// subscribe registers o with values queued before any value sent later. o is
+// delivered asynchronously by a mailbox if s is in async mode or there are
+// queued values, so that subscribing never waits for o to receive.
+func (s *Subject[T]) subscribe(ctx context.Context, o Subscriber[T], queued []T) {
 	s.mu.Lock()
 	if s.err != nil {
 		err := s.err
@@ -95,13 +203,29 @@ func (s *Subject[T]) Subscribe(o Subscriber[T]) {
 	}
 
 	if s.subs == nil {
-		s.subs = map[Subscriber[T]]struct{}{}
+		s.subs = map[Subscriber[T]]*mailbox[T]{}
+	}
+	if _, ok := s.subs[o]; ok {
+		s.mu.Unlock()
+		return // already subscribed
+	}
+	var mb *mailbox[T]
+	if s.async || len(queued) > 0 {
+		mb = newMailbox[T]()
+		for _, x := range queued {
+			mb.push(x)
+		}
+		go s.dispatch(o, mb)
 	}
-	s.subs[o] = struct{}{}
+	s.subs[o] = mb
 	s.mu.Unlock()
 
 	go func() {
-		<-o.Done()
+		select {
+		case <-o.Done():
+		case <-ctx.Done():
+			o.CancelCause(context.Cause(ctx))
+		}
 
 		s.mu.Lock()
 		delete(s.subs, o)
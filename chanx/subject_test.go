@@ -1,13 +1,17 @@
 package chanx_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"sync"
+	"testing"
 	"time"
 
 	"github.com/xoctopus/x/chanx"
 	"github.com/xoctopus/x/iterx"
+	. "github.com/xoctopus/x/testx"
 )
 
 func ExampleSubject() {
@@ -66,3 +70,98 @@ func ExampleSubject() {
 	// Obs1 Error: completed
 	// Obs2 Error: completed
 }
+
+func TestSubject_ObserveContext(t *testing.T) {
+	cause := errors.New("request finished")
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	s := &chanx.Subject[int]{}
+	o1 := s.ObserveContext(ctx)
+	o2 := s.Observe()
+	go func() {
+		for range o2.Value() {
+		}
+	}()
+
+	go s.Send(1)
+	Expect(t, <-o1.Value(), Equal(1))
+
+	cancel(cause)
+	<-o1.Done()
+	Expect(t, o1.Err(), IsError(cause))
+	Expect(t, o2.Err(), BeNil[error]())
+
+	s.CancelCause(nil)
+	<-o2.Done()
+	Expect(t, o2.Err(), IsError(chanx.ErrCompleted))
+
+	t.Run("Replay", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+
+		s := chanx.NewBehaviorSubject(1)
+		o := s.ObserveContext(ctx)
+		Expect(t, <-o.Value(), Equal(1))
+		cancel(cause)
+		<-o.Done()
+		Expect(t, o.Err(), IsError(cause))
+
+		r := chanx.NewReplaySubject[int](1, 0)
+		r.Send(1)
+		o = r.ObserveContext(ctx)
+		<-o.Done()
+		Expect(t, o.Err(), IsError(cause))
+	})
+}
+
+type panicSubscriber struct {
+	chanx.NotifiableObserver[int]
+}
+
+func (s *panicSubscriber) Send(x int) {
+	if x < 0 {
+		panic(fmt.Errorf("negative value %d", x))
+	}
+	s.NotifiableObserver.Send(x)
+}
+
+func TestWithRecover(t *testing.T) {
+	var (
+		recovered = make(chan *chanx.PanicError, 1)
+		bad       = &panicSubscriber{chanx.NewNotifiableObserver[int]()}
+		s         = chanx.NewSubject(chanx.WithRecover(
+			func(_ chanx.Subscriber[int], err *chanx.PanicError) { recovered <- err },
+		))
+	)
+
+	s.Subscribe(bad)
+	good := s.Observe()
+	go func() {
+		s.Send(-1)
+		s.Send(1)
+	}()
+	Expect(t, <-good.Value(), Equal(-1))
+	Expect(t, <-good.Value(), Equal(1))
+
+	err := <-recovered
+	Expect(t, err.Error(), Equal("subscriber panicked: negative value -1"))
+	Expect(t, string(err.Stack), ContainsSubString("panicSubscriber"))
+	Expect(t, errors.Unwrap(err), ErrorEqual("negative value -1"))
+
+	<-bad.Done()
+	Expect(t, bad.Err(), AsErrorType[*chanx.PanicError]())
+	Expect(t, good.Err(), BeNil[error]())
+	s.CancelCause(nil)
+
+	t.Run("WithoutRecover", func(t *testing.T) {
+		s := &chanx.Subject[int]{}
+		s.Subscribe(&panicSubscriber{chanx.NewNotifiableObserver[int]()})
+		ExpectPanic[error](t, func() { s.Send(-1) })
+	})
+	t.Run("Replay", func(t *testing.T) {
+		s := chanx.NewBehaviorSubject(-1, chanx.WithRecover[int](nil))
+		bad := &panicSubscriber{chanx.NewNotifiableObserver[int]()}
+		s.Subscribe(bad)
+		<-bad.Done()
+		Expect(t, bad.Err(), AsErrorType[*chanx.PanicError]())
+	})
+}
@@ -29,6 +29,12 @@ type NotifiableObserver[T any] interface {
 	ValueNotifier[T]
 }
 
+type BufferedObserver[T any] interface {
+	NotifiableObserver[T]
+
+	Dropped() uint64
+}
+
 type Subscriber[T any] interface {
 	ValueNotifier[T]
 	Cancelable
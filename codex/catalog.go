@@ -0,0 +1,158 @@
+package codex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Entry describes a code in error-code catalog
+type Entry struct {
+	// Type is the full qualified name of code type
+	Type string `json:"type"`
+	// Kind is the underlying integer kind of code type. eg: `int8`, `uint64`
+	Kind string `json:"kind"`
+	// Code is the decimal code value
+	Code json.Number `json:"code"`
+	// Identifier is the stable string identifier of code
+	Identifier string `json:"identifier"`
+	// Message is the default message of code
+	Message string `json:"message"`
+	// HTTP status of code
+	HTTP int `json:"http"`
+	// Canonical is gRPC-like canonical status name
+	Canonical string `json:"canonical"`
+}
+
+// Catalog is an error-code catalog for documentation. catalogs of different
+// code types can be concatenated by slices.Concat.
+type Catalog []Entry
+
+// Enumerate returns the catalog of codes. if no code is given, codes are
+// enumerated by `Values() []C` of C, which is generated by codexgen. transport
+// status is resolved by the registered metadata of C.
+func Enumerate[C Code](codes ...C) Catalog {
+	if len(codes) == 0 {
+		if x, ok := any(*new(C)).(interface{ Values() []C }); ok {
+			codes = x.Values()
+		}
+	}
+
+	t := reflect.TypeFor[C]()
+	name, kind := nameof(t), t.Kind().String()
+	catalog := make(Catalog, 0, len(codes))
+	for _, c := range codes {
+		m, ok := MetaOf(c)
+		if !ok {
+			m = Meta{
+				Identifier: fmt.Sprintf("%T[%d]", c, c),
+				HTTP:       CanonicalUnknown.HTTP(),
+				Canonical:  CanonicalUnknown,
+			}
+		}
+		catalog = append(catalog, Entry{
+			Type:       name,
+			Kind:       kind,
+			Code:       json.Number(fmt.Sprintf("%d", c)),
+			Identifier: m.Identifier,
+			Message:    (&coderr[C]{code: c}).template(""),
+			HTTP:       m.HTTP,
+			Canonical:  m.Canonical.String(),
+		})
+	}
+	return catalog
+}
+
+// WriteMarkdown renders catalog as markdown tables grouped by code type
+func (c Catalog) WriteMarkdown(w io.Writer) error {
+	b := &strings.Builder{}
+	for i, e := range c {
+		if i == 0 || c[i-1].Type != e.Type {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			_, _ = fmt.Fprintf(b, "### %s\n\n", e.Type)
+			b.WriteString("| Code | Identifier | HTTP | Canonical | Message |\n")
+			b.WriteString("| ---: | :--- | ---: | :--- | :--- |\n")
+		}
+		_, _ = fmt.Fprintf(
+			b, "| %s | %s | %d | %s | %s |\n",
+			e.Code, cell(e.Identifier), e.HTTP, e.Canonical, cell(e.Message),
+		)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON renders catalog as an indented JSON array of entries
+func (c Catalog) WriteJSON(w io.Writer) error {
+	if c == nil {
+		c = Catalog{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// WriteOpenAPI renders catalog as OpenAPI components. each code type is an
+// integer enum schema, with `x-enum-varnames` and `x-enum-descriptions` for
+// identifiers and messages. schemas are named by full qualified type names
+// with characters invalid in component names replaced by `_`, it returns an
+// error if names of different types collide after replacement. the schema
+// format is the integer kind of code type, such as `int32` or `uint64`.
+func (c Catalog) WriteOpenAPI(w io.Writer) error {
+	type schema struct {
+		Type         string        `json:"type"`
+		Format       string        `json:"format"`
+		Description  string        `json:"description"`
+		Enum         []json.Number `json:"enum"`
+		Varnames     []string      `json:"x-enum-varnames"`
+		Descriptions []string      `json:"x-enum-descriptions"`
+	}
+
+	schemas := map[string]*schema{}
+	for _, e := range c {
+		key := component(e.Type)
+		s, ok := schemas[key]
+		if !ok {
+			s = &schema{Type: "integer", Format: formats[e.Kind], Description: e.Type}
+			if s.Format == "" {
+				s.Format = e.Kind
+			}
+			schemas[key] = s
+		} else if s.Description != e.Type {
+			return fmt.Errorf("schema name %s collides between %s and %s", key, s.Description, e.Type)
+		}
+		s.Enum = append(s.Enum, e.Code)
+		s.Varnames = append(s.Varnames, e.Identifier)
+		s.Descriptions = append(s.Descriptions, e.Message)
+	}
+
+	doc := map[string]any{"components": map[string]any{"schemas": schemas}}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// formats maps platform dependent kinds to OpenAPI formats
+var formats = map[string]string{"int": "int64", "uint": "uint64"}
+
+// component returns OpenAPI component name of full qualified type name by
+// replacing characters out of `[A-Za-z0-9._-]` with `_`
+func component(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9',
+			r == '.', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// cell escapes s as a markdown table cell
+func cell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(s)
+}
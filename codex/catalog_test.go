@@ -0,0 +1,132 @@
+package codex_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+
+	. "github.com/xoctopus/x/codex"
+	. "github.com/xoctopus/x/testx"
+)
+
+type PaymentCode int64
+
+const (
+	PAYMENT_CODE__DECLINED PaymentCode = iota + 1
+	PAYMENT_CODE__TIMEOUT
+)
+
+func (c PaymentCode) Values() []PaymentCode {
+	return []PaymentCode{PAYMENT_CODE__DECLINED, PAYMENT_CODE__TIMEOUT}
+}
+
+func (c PaymentCode) Message() string {
+	switch c {
+	case PAYMENT_CODE__DECLINED:
+		return "card declined | retry with another card"
+	default:
+		return "payment timeout"
+	}
+}
+
+func ExampleCatalog_WriteMarkdown() {
+	catalog := slices.Concat(
+		Enumerate(ORDER_CODE__NOT_FOUND, ORDER_CODE__PAID),
+		Enumerate[PaymentCode](),
+	)
+	_ = catalog.WriteMarkdown(os.Stdout)
+
+	// Output:
+	// ### github.com/xoctopus/x/codex_test.OrderCode
+	//
+	// | Code | Identifier | HTTP | Canonical | Message |
+	// | ---: | :--- | ---: | :--- | :--- |
+	// | 1 | ORDER_NOT_FOUND | 404 | NOT_FOUND | order code 1 |
+	// | 2 | ORDER_PAID | 409 | FAILED_PRECONDITION | order code 2 |
+	//
+	// ### github.com/xoctopus/x/codex_test.PaymentCode
+	//
+	// | Code | Identifier | HTTP | Canonical | Message |
+	// | ---: | :--- | ---: | :--- | :--- |
+	// | 1 | codex_test.PaymentCode[1] | 500 | UNKNOWN | card declined \| retry with another card |
+	// | 2 | codex_test.PaymentCode[2] | 500 | UNKNOWN | payment timeout |
+}
+
+func TestCatalog(t *testing.T) {
+	t.Run("Enumerate", func(t *testing.T) {
+		Expect(t, Enumerate[OrderCode](), HaveLen[Catalog](0))
+		Expect(t, Enumerate[PaymentCode](), HaveLen[Catalog](2))
+
+		catalog := Enumerate(ECODE__REASON1)
+		Expect(t, catalog[0], Equal(Entry{
+			Type:       "github.com/xoctopus/x/codex_test.ECode",
+			Kind:       "int8",
+			Code:       "2",
+			Identifier: "codex_test.ECode[2]",
+			Message:    "[region:2] reason1",
+			HTTP:       500,
+			Canonical:  "UNKNOWN",
+		}))
+	})
+	t.Run("WriteJSON", func(t *testing.T) {
+		b := bytes.NewBuffer(nil)
+		Expect(t, Catalog(nil).WriteJSON(b), Succeed())
+		Expect(t, b.String(), Equal("[]\n"))
+
+		b.Reset()
+		Expect(t, Enumerate(ORDER_CODE__PAID).WriteJSON(b), Succeed())
+
+		var entries []Entry
+		Expect(t, json.Unmarshal(b.Bytes(), &entries), Succeed())
+		Expect(t, entries, Equal([]Entry(Enumerate(ORDER_CODE__PAID))))
+	})
+	t.Run("WriteOpenAPI", func(t *testing.T) {
+		b := bytes.NewBuffer(nil)
+		catalog := slices.Concat(Enumerate(ORDER_CODE__NOT_FOUND), Enumerate[PaymentCode]())
+		Expect(t, catalog.WriteOpenAPI(b), Succeed())
+
+		var doc struct {
+			Components struct {
+				Schemas map[string]struct {
+					Type         string        `json:"type"`
+					Format       string        `json:"format"`
+					Enum         []json.Number `json:"enum"`
+					Varnames     []string      `json:"x-enum-varnames"`
+					Descriptions []string      `json:"x-enum-descriptions"`
+				} `json:"schemas"`
+			} `json:"components"`
+		}
+		Expect(t, json.Unmarshal(b.Bytes(), &doc), Succeed())
+		Expect(t, len(doc.Components.Schemas), Equal(2))
+
+		payment := doc.Components.Schemas["github.com_xoctopus_x_codex_test.PaymentCode"]
+		Expect(t, payment.Type, Equal("integer"))
+		Expect(t, payment.Format, Equal("int64"))
+		Expect(t, payment.Enum, Equal([]json.Number{"1", "2"}))
+		Expect(t, payment.Varnames, Equal([]string{"codex_test.PaymentCode[1]", "codex_test.PaymentCode[2]"}))
+		Expect(t, payment.Descriptions[1], Equal("payment timeout"))
+
+		order := doc.Components.Schemas["github.com_xoctopus_x_codex_test.OrderCode"]
+		Expect(t, order.Format, Equal("uint16"))
+		Expect(t, order.Varnames, Equal([]string{"ORDER_NOT_FOUND"}))
+
+		b.Reset()
+		catalog = Enumerate(Compose[NamespacedCode](0xFFFFFFFF, 1))
+		Expect(t, catalog.WriteOpenAPI(b), Succeed())
+		Expect(t, b.String(), ContainsSubString(`"format": "uint64"`))
+		Expect(t, b.String(), ContainsSubString("18446744069414584321"))
+	})
+	t.Run("SchemaNameCollision", func(t *testing.T) {
+		catalog := Catalog{
+			{Type: "example.com/orders/v1.Ecode", Kind: "int32", Code: "1"},
+			{Type: "example.com/billing/v1.Ecode", Kind: "int32", Code: "1"},
+			{Type: "example.com/orders_v1.Ecode", Kind: "int32", Code: "1"},
+		}
+		Expect(t, catalog[:2].WriteOpenAPI(bytes.NewBuffer(nil)), Succeed())
+		Expect(t, catalog.WriteOpenAPI(bytes.NewBuffer(nil)), ErrorContains(
+			"schema name example.com_orders_v1.Ecode collides between example.com/orders/v1.Ecode and example.com/orders_v1.Ecode",
+		))
+	})
+}
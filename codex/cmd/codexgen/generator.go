@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Generator scans code types and their constants in a package directory
+type Generator struct {
+	fset *token.FileSet
+	pkg  *types.Package
+	info *types.Info
+	// files are parsed non-test go files
+	files []*ast.File
+}
+
+// Load parses and type-checks go files in dir. imported packages are stubbed
+// because only constant values are needed.
+func Load(dir string) (*Generator, error) {
+	g := &Generator{fset: token.NewFileSet()}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	for _, filename := range matches {
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(g.fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		g.files = append(g.files, f)
+	}
+	if len(g.files) == 0 {
+		return nil, fmt.Errorf("no go files in %s", dir)
+	}
+
+	g.info = &types.Info{
+		Defs:  map[*ast.Ident]types.Object{},
+		Types: map[ast.Expr]types.TypeAndValue{},
+	}
+	conf := types.Config{
+		Importer: stub{},
+		Error:    func(error) {}, // tolerate errors caused by stubbed imports
+	}
+	g.pkg, _ = conf.Check(g.files[0].Name.Name, g.fset, g.files, g.info)
+	return g, nil
+}
+
+type stub struct{}
+
+func (stub) Import(path string) (*types.Package, error) {
+	pkg := types.NewPackage(path, filepath.Base(path))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// CodeType describes a code type and its constants
+type CodeType struct {
+	Package string
+	Name    string
+	// Prefix is the message prefix, declared by `@def` in type doc or package
+	// name by default
+	Prefix string
+	Values []*CodeValue
+}
+
+// CodeValue describes a code constant
+type CodeValue struct {
+	Name       string
+	Value      string
+	Identifier string
+	Message    string
+	// Canonical is declared by `@canonical` in comment. eg: `NOT_FOUND`
+	Canonical string
+	// HTTP is declared by `@http` in comment. eg: `404`
+	HTTP int
+}
+
+// Scan collects constants of the named type
+func (g *Generator) Scan(name string) (*CodeType, error) {
+	obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in package %s", name, g.pkg.Name())
+	}
+	basic, ok := obj.Type().Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsInteger == 0 {
+		return nil, fmt.Errorf("type %s is not an integer type", name)
+	}
+
+	t := &CodeType{Package: g.pkg.Name(), Name: name, Prefix: g.pkg.Name()}
+
+	seen := map[string]bool{}
+	for _, f := range g.files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.Name == name {
+						doc := s.Doc
+						if doc == nil {
+							doc = gen.Doc
+						}
+						if def, ok := annotation(doc.Text(), "@def"); ok {
+							t.Prefix = def
+						}
+					}
+				case *ast.ValueSpec:
+					for _, ident := range s.Names {
+						c, ok := g.info.Defs[ident].(*types.Const)
+						if !ok || !types.Identical(c.Type(), obj.Type()) || ident.Name == "_" {
+							continue
+						}
+						v := &CodeValue{Name: ident.Name, Value: c.Val().ExactString()}
+						if seen[v.Value] {
+							continue // aliased value
+						}
+						seen[v.Value] = true
+
+						comment := s.Comment.Text()
+						if comment == "" {
+							comment = s.Doc.Text()
+						}
+						v.Identifier = identifier(name, ident.Name)
+						if err := v.parse(comment); err != nil {
+							return nil, fmt.Errorf("%s: %w", ident.Name, err)
+						}
+						if c.Val().Kind() != constant.Int {
+							return nil, fmt.Errorf("%s: invalid constant value", ident.Name)
+						}
+						t.Values = append(t.Values, v)
+					}
+				}
+			}
+		}
+	}
+	if len(t.Values) == 0 {
+		return nil, fmt.Errorf("no constant of type %s", name)
+	}
+	return t, nil
+}
+
+// parse parses message and annotations from comment
+func (v *CodeValue) parse(comment string) error {
+	var words []string
+	fields := strings.Fields(comment)
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "@canonical", "@http":
+			if i+1 >= len(fields) {
+				return fmt.Errorf("missing value of %s", fields[i])
+			}
+			if fields[i] == "@http" {
+				status, err := strconv.Atoi(fields[i+1])
+				if err != nil {
+					return fmt.Errorf("invalid http status: %w", err)
+				}
+				v.HTTP = status
+			} else {
+				v.Canonical = fields[i+1]
+			}
+			i++
+		default:
+			words = append(words, fields[i])
+		}
+	}
+	v.Message = strings.Join(words, " ")
+	if v.Message == "" {
+		v.Message = strings.ToLower(strings.ReplaceAll(v.Identifier, "_", " "))
+	}
+	return nil
+}
+
+// annotation finds `key value` in doc
+func annotation(doc, key string) (string, bool) {
+	for _, line := range strings.Split(doc, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			return fields[1], true
+		}
+	}
+	return "", false
+}
+
+// identifier trims type name prefix of constant name.
+// eg: `ECODE__NOT_FOUND` of `Ecode` => `NOT_FOUND`
+func identifier(typename, name string) string {
+	if id, ok := strings.CutPrefix(name, strings.ToUpper(typename)); ok {
+		if id = strings.TrimLeft(id, "_"); id != "" {
+			return id
+		}
+	}
+	return name
+}
+
+var canonicals = map[string]string{
+	"OK":                  "CanonicalOK",
+	"CANCELED":            "CanonicalCanceled",
+	"UNKNOWN":             "CanonicalUnknown",
+	"INVALID_ARGUMENT":    "CanonicalInvalidArgument",
+	"DEADLINE_EXCEEDED":   "CanonicalDeadlineExceeded",
+	"NOT_FOUND":           "CanonicalNotFound",
+	"ALREADY_EXISTS":      "CanonicalAlreadyExists",
+	"PERMISSION_DENIED":   "CanonicalPermissionDenied",
+	"RESOURCE_EXHAUSTED":  "CanonicalResourceExhausted",
+	"FAILED_PRECONDITION": "CanonicalFailedPrecondition",
+	"ABORTED":             "CanonicalAborted",
+	"OUT_OF_RANGE":        "CanonicalOutOfRange",
+	"UNIMPLEMENTED":       "CanonicalUnimplemented",
+	"INTERNAL":            "CanonicalInternal",
+	"UNAVAILABLE":         "CanonicalUnavailable",
+	"DATA_LOSS":           "CanonicalDataLoss",
+	"UNAUTHENTICATED":     "CanonicalUnauthenticated",
+}
+
+var tmpl = template.Must(template.New("code").Funcs(template.FuncMap{
+	"canonical": func(name string) string {
+		if name == "" {
+			return "CanonicalUnknown"
+		}
+		return canonicals[name]
+	},
+}).Parse(`// Code generated by codexgen DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"fmt"
+
+	"github.com/xoctopus/x/codex"
+)
+
+func init() {
+	codex.Register(func(e {{ .Name }}) codex.Meta {
+		switch e {
+		default:
+			return codex.Meta{Identifier: e.String(), Canonical: codex.CanonicalUnknown}
+		{{- range .Values }}
+		case {{ .Name }}:
+			return codex.Meta{Identifier: "{{ .Identifier }}", Canonical: codex.{{ canonical .Canonical }}{{ if .HTTP }}, HTTP: {{ .HTTP }}{{ end }}}
+		{{- end }}
+		}
+	})
+}
+
+func (e {{ .Name }}) Values() []{{ .Name }} {
+	return []{{ .Name }}{
+		{{- range .Values }}
+		{{ .Name }},
+		{{- end }}
+	}
+}
+
+func (e {{ .Name }}) String() string {
+	switch e {
+	default:
+		return fmt.Sprintf("{{ .Name }}(%d)", e)
+	{{- range .Values }}
+	case {{ .Name }}:
+		return "{{ .Identifier }}"
+	{{- end }}
+	}
+}
+
+func (e {{ .Name }}) Message() string {
+	switch e {
+	default:
+		return fmt.Sprintf("[{{ .Prefix }}:%d] unknown", e)
+	{{- $prefix := .Prefix }}
+	{{- range .Values }}
+	case {{ .Name }}:
+		return {{ printf "[%s:%s] %s" $prefix .Value .Message | printf "%q" }}
+	{{- end }}
+	}
+}
+`))
+
+// Generate renders source code of t
+func Generate(t *CodeType) ([]byte, error) {
+	for _, v := range t.Values {
+		if _, ok := canonicals[v.Canonical]; v.Canonical != "" && !ok {
+			return nil, fmt.Errorf("%s: unknown canonical status %s", v.Name, v.Canonical)
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := tmpl.Execute(buf, t); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// Filename returns default output filename of code type. eg: `ecode_genx_code.go`
+func Filename(name string) string {
+	return strings.ToLower(name) + "_genx_code.go"
+}
+
+// Run generates code for each type name in dir
+func Run(dir string, names ...string) error {
+	if len(names) == 0 {
+		return errors.New("no type specified")
+	}
+	g, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		t, err := g.Scan(name)
+		if err != nil {
+			return err
+		}
+		code, err := Generate(t)
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(filepath.Join(dir, Filename(name)), code, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/xoctopus/x/testx"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	// generated code in internal/example is compiled and tested as golden
+	src, err := os.ReadFile(filepath.Join("internal", "example", "ecode.go"))
+	Expect(t, err, Succeed())
+	Expect(t, os.WriteFile(filepath.Join(dir, "ecode.go"), src, 0o644), Succeed())
+
+	Expect(t, Run(dir, "Ecode", "Status"), Succeed())
+
+	for _, name := range []string{"Ecode", "Status"} {
+		filename := Filename(name)
+		generated, err := os.ReadFile(filepath.Join(dir, filename))
+		Expect(t, err, Succeed())
+		golden, err := os.ReadFile(filepath.Join("internal", "example", filename))
+		Expect(t, err, Succeed())
+		Expect(t, string(generated), Equal(string(golden)))
+	}
+
+	t.Run("Failed", func(t *testing.T) {
+		Expect(t, Run(dir), ErrorEqual("no type specified"))
+		Expect(t, Run(t.TempDir(), "Ecode"), ErrorContains("no go files"))
+		Expect(t, Run(dir, "Unknown"), ErrorContains("type Unknown not found"))
+	})
+}
+
+func TestGenerator_Scan(t *testing.T) {
+	load := func(t *testing.T, src string) *Generator {
+		dir := t.TempDir()
+		Expect(t, os.WriteFile(filepath.Join(dir, "code.go"), []byte(src), 0o644), Succeed())
+		g, err := Load(dir)
+		Expect(t, err, Succeed())
+		return g
+	}
+
+	cases := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{"NotInteger", "package x\ntype Code string\nconst A Code = \"a\"", "not an integer type"},
+		{"NoConstant", "package x\ntype Code int", "no constant of type Code"},
+		{"MissingAnnotation", "package x\ntype Code int\nconst A Code = 1 // a @http", "missing value of @http"},
+		{"InvalidHTTP", "package x\ntype Code int\nconst A Code = 1 // a @http x", "invalid http status"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := load(t, c.src).Scan("Code")
+			Expect(t, err, ErrorContains(c.err))
+		})
+	}
+
+	t.Run("UnknownCanonical", func(t *testing.T) {
+		code, err := load(t, "package x\ntype Code int\nconst A Code = 1 // a @canonical X").Scan("Code")
+		Expect(t, err, Succeed())
+		_, err = Generate(code)
+		Expect(t, err, ErrorContains("unknown canonical status X"))
+	})
+	t.Run("Identifier", func(t *testing.T) {
+		Expect(t, identifier("Ecode", "ECODE__NOT_FOUND"), Equal("NOT_FOUND"))
+		Expect(t, identifier("Ecode", "ECODE"), Equal("ECODE"))
+		Expect(t, identifier("Ecode", "NOT_FOUND"), Equal("NOT_FOUND"))
+	})
+}
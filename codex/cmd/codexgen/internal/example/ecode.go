@@ -0,0 +1,29 @@
+package example
+
+//go:generate go run github.com/xoctopus/x/codex/cmd/codexgen -type Ecode,Status
+
+import "github.com/xoctopus/x/textx"
+
+// Ecode defines error codes of order service
+// @def order
+type Ecode int8
+
+const (
+	ECODE_UNDEFINED  Ecode = iota
+	ECODE__NOT_FOUND       // order not found @canonical NOT_FOUND
+	ECODE__PAID            // order paid @canonical FAILED_PRECONDITION @http 409
+	// order expired at 100%
+	ECODE__EXPIRED
+	ECODE__ALIAS = ECODE__EXPIRED
+)
+
+const ECODE__OVERFLOW Ecode = -1 // overflowed
+
+type Status uint8
+
+const (
+	STATUS__OK     Status = iota + 1 // ok
+	STATUS__FAILED                   // failed
+)
+
+var _ = textx.ECODE_UNDEFINED
@@ -0,0 +1,42 @@
+package example_test
+
+import (
+	"testing"
+
+	"github.com/xoctopus/x/codex"
+	. "github.com/xoctopus/x/codex/cmd/codexgen/internal/example"
+	. "github.com/xoctopus/x/testx"
+)
+
+func TestGenerated(t *testing.T) {
+	errs := make([]string, 0)
+	for _, c := range ECODE_UNDEFINED.Values() {
+		errs = append(errs, codex.New(c).Error())
+	}
+	errs = append(errs, codex.New(Ecode(100)).Error())
+	for _, c := range STATUS__OK.Values() {
+		errs = append(errs, codex.New(c).Error())
+	}
+	Expect(t, errs, Equal([]string{
+		"[order:0] undefined",
+		"[order:1] order not found",
+		"[order:2] order paid",
+		"[order:3] order expired at 100%",
+		"[order:-1] overflowed",
+		"[order:100] unknown",
+		"[example:1] ok",
+		"[example:2] failed",
+	}))
+
+	Expect(t, ECODE__EXPIRED.Message(), Equal("[order:3] order expired at 100%"))
+	Expect(t, codex.Errorf(ECODE__EXPIRED, "id %d", 1).Error(), Equal("[order:3] order expired at 100%. id 1"))
+
+	catalog := codex.Enumerate(ECODE__EXPIRED)
+	Expect(t, catalog[0].Message, Equal("[order:3] order expired at 100%"))
+	Expect(t, catalog[0].Identifier, Equal("EXPIRED"))
+
+	m := codex.StatusOf(codex.New(ECODE__PAID))
+	Expect(t, m, Equal(codex.Meta{Identifier: "PAID", HTTP: 409, Canonical: codex.CanonicalFailedPrecondition}))
+	Expect(t, ECODE__NOT_FOUND.String(), Equal("NOT_FOUND"))
+	Expect(t, Ecode(100).String(), Equal("Ecode(100)"))
+}
@@ -0,0 +1,45 @@
+// Command codexgen generates Message, String, Values and codex registry hooks
+// for code types. it is designed for go generate:
+//
+//	//go:generate go run github.com/xoctopus/x/codex/cmd/codexgen -type Ecode
+//
+// the message of each constant is read from its comment, and the prefix of
+// messages is declared by `@def` in type doc. `@canonical` and `@http` in
+// comments declare the transport status registered to codex.
+//
+//	// Ecode defines error codes of order service
+//	// @def order
+//	type Ecode int8
+//
+//	const (
+//		ECODE_UNDEFINED Ecode = iota
+//		ECODE__NOT_FOUND // order not found @canonical NOT_FOUND
+//		ECODE__PAID      // order paid @canonical FAILED_PRECONDITION @http 409
+//	)
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+)
+
+func main() {
+	var (
+		names string
+		dir   string
+	)
+	flag.StringVar(&names, "type", "", "comma-separated list of code type names")
+	flag.StringVar(&dir, "dir", ".", "package directory")
+	flag.Parse()
+
+	var types []string
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			types = append(types, name)
+		}
+	}
+	if err := Run(dir, types...); err != nil {
+		log.Fatalf("codexgen: %v", err)
+	}
+}
@@ -3,6 +3,9 @@ package codex
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 )
 
 // Code is a generic interface that represents an error code with an underlying
@@ -10,7 +13,17 @@ import (
 // any integer-based type. The Message method returns the error description
 // associated with the code.
 type Code interface {
-	~int | ~int8 | ~int16 | ~int32 | ~uint | ~uint8 | ~uint16 | ~uint32
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// Compose composes a 64-bit namespaced code as namespace<<32 | code
+func Compose[C ~int64 | ~uint64](namespace, code uint32) C {
+	return C(uint64(namespace)<<32 | uint64(code))
+}
+
+// Decompose splits a 64-bit namespaced code composed by Compose
+func Decompose[C ~int64 | ~uint64](c C) (namespace, code uint32) {
+	return uint32(uint64(c) >> 32), uint32(uint64(c))
 }
 
 type _ interface {
@@ -34,25 +47,25 @@ type Error[C Code] interface {
 }
 
 func New[C Code](e C) error {
-	return &coderr[C]{code: e}
+	return &coderr[C]{code: e, stack: callers()}
 }
 
 func Errorf[C Code](e C, format string, args ...any) error {
-	return &coderr[C]{code: e, msg: format, args: args}
+	return &coderr[C]{code: e, msg: format, args: args, stack: callers()}
 }
 
 func Wrap[C Code](e C, cause error) error {
 	if cause == nil {
 		return nil
 	}
-	return &coderr[C]{code: e, cause: cause, args: []any{cause}}
+	return &coderr[C]{code: e, cause: cause, args: []any{cause}, stack: callers()}
 }
 
 func Wrapf[C Code](e C, cause error, format string, args ...any) error {
 	if cause == nil {
 		return nil
 	}
-	return &coderr[C]{code: e, cause: cause, msg: format, args: append(args, cause)}
+	return &coderr[C]{code: e, cause: cause, msg: format, args: append(args, cause), stack: callers()}
 }
 
 func IsCode[C Code](e error, code C) bool {
@@ -79,24 +92,31 @@ func AsCode[C Code](e error) (C, bool) {
 }
 
 type coderr[C Code] struct {
-	code  C
-	msg   string
-	args  []any
-	cause error
+	code   C
+	msg    string
+	args   []any
+	cause  error
+	fields []slog.Attr
+	stack  stack
 }
 
 func (e *coderr[C]) Error() string {
 	msg := fmt.Sprintf("%T[%d]", e.code, e.code)
 	if x, ok := any(e.code).(interface{ Message() string }); ok {
-		msg = x.Message()
+		// message is plain text but msg is used as format
+		msg = strings.ReplaceAll(x.Message(), "%", "%%")
 	}
 	if len(e.msg) > 0 {
 		msg += ". " + e.msg
 	}
+	args := e.args
 	if e.cause != nil {
-		msg += ". [cause: %+v]"
+		msg += ". [cause: %s]"
+		// cause is formatted by its Error() to keep fields and stack of cause
+		// out of the message
+		args = append(slices.Clip(args[:len(args)-1]), e.cause.Error())
 	}
-	return fmt.Sprintf(msg, e.args...)
+	return fmt.Sprintf(msg, args...)
 }
 
 func (e *coderr[C]) Code() C {
@@ -111,3 +131,27 @@ func (e *coderr[C]) Is(err error) bool {
 	target, ok := errors.AsType[*coderr[C]](err)
 	return ok && target.Code() == e.Code()
 }
+
+func (e *coderr[C]) with(attrs []slog.Attr) error {
+	c := *e
+	c.fields = append(slices.Clip(e.fields), attrs...)
+	return &c
+}
+
+func (e *coderr[C]) fieldList() []slog.Attr {
+	return e.fields
+}
+
+func (e *coderr[C]) stackTrace() stack {
+	return e.stack
+}
+
+// Format implements fmt.Formatter. `%+v` prints fields and stack trace
+func (e *coderr[C]) Format(s fmt.State, verb rune) {
+	format(e, s, verb)
+}
+
+// LogValue implements slog.LogValuer. fields in chain are emitted as attributes
+func (e *coderr[C]) LogValue() slog.Value {
+	return logValue(e, slog.Any("code", e.code))
+}
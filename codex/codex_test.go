@@ -94,3 +94,42 @@ func TestIs(t *testing.T) {
 	_, ok2 := AsCode[ECode2](New(ECODE__REASON2))
 	Expect(t, ok2, BeFalse())
 }
+
+type NamespacedCode uint64
+
+func (c NamespacedCode) Message() string {
+	namespace, code := Decompose(c)
+	return fmt.Sprintf("service %d code %d", namespace, code)
+}
+
+func ExampleCompose() {
+	c := Compose[NamespacedCode](0xFFFF0001, 404)
+	fmt.Println(Decompose(c))
+	fmt.Println(New(c))
+
+	// Output:
+	// 4294901761 404
+	// service 4294901761 code 404
+}
+
+func TestNamespacedCode(t *testing.T) {
+	RegisterType[NamespacedCode]()
+
+	for _, c := range []NamespacedCode{
+		Compose[NamespacedCode](1, 2),
+		Compose[NamespacedCode](0xFFFFFFFF, 0xFFFFFFFF),
+	} {
+		data, err := Marshal(New(c))
+		Expect(t, err, Succeed())
+
+		var decoded error
+		Expect(t, Unmarshal(data, &decoded), Succeed())
+		Expect(t, decoded, IsCodeError(c))
+	}
+
+	signed := Compose[int64](0x80000000, 1)
+	Expect(t, signed < 0, BeTrue())
+	namespace, code := Decompose(signed)
+	Expect(t, namespace, Equal[uint32](0x80000000))
+	Expect(t, code, Equal[uint32](1))
+}
@@ -0,0 +1,129 @@
+package codex
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Join returns an *Errors holding the non-nil errs, or nil if there is none.
+// unlike errors.Join, nested *Errors are flattened so that codes of all members
+// are kept in one list.
+func Join(errs ...error) error {
+	e := &Errors{}
+	e.Append(errs...)
+	return e.Err()
+}
+
+// Errors is a list of errors preserving codes of members. errors.Is, errors.As
+// and IsCode match if any member matches. a zero Errors is ready to use.
+type Errors struct {
+	errs []error
+}
+
+// Append appends non-nil errs in order
+func (e *Errors) Append(errs ...error) {
+	for _, err := range errs {
+		switch x := err.(type) {
+		case nil:
+		case *Errors:
+			e.errs = append(e.errs, x.errs...)
+		default:
+			e.errs = append(e.errs, err)
+		}
+	}
+}
+
+// Err returns e if it has any member, otherwise nil
+func (e *Errors) Err() error {
+	if e == nil || len(e.errs) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *Errors) Len() int {
+	return len(e.errs)
+}
+
+func (e *Errors) Unwrap() []error {
+	return slices.Clone(e.errs)
+}
+
+// Error joins messages of members by newline in order
+func (e *Errors) Error() string {
+	msgs := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Codes returns distinct codes of all types in members in order
+func (e *Errors) Codes() []any {
+	var codes []any
+	for _, err := range chain(e) {
+		if x, ok := err.(interface{ value() any }); ok && !slices.Contains(codes, x.value()) {
+			codes = append(codes, x.value())
+		}
+	}
+	return codes
+}
+
+// Format implements fmt.Formatter. `%+v` prints each member with `%+v`
+func (e *Errors) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		for i, err := range e.errs {
+			if i > 0 {
+				_, _ = io.WriteString(s, "\n")
+			}
+			_, _ = fmt.Fprintf(s, "%+v", err)
+		}
+		return
+	}
+	format(e, s, verb)
+}
+
+// LogValue implements slog.LogValuer. members are emitted as a group keyed by
+// their indexes
+func (e *Errors) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(e.errs))
+	for i, err := range e.errs {
+		attrs = append(attrs, slog.Any(strconv.Itoa(i), err))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// CodesOf returns distinct codes of type C in err's chain in order
+func CodesOf[C Code](err error) []C {
+	var codes []C
+	for _, e := range chain(err) {
+		if x, ok := e.(*coderr[C]); ok && !slices.Contains(codes, x.code) {
+			codes = append(codes, x.code)
+		}
+	}
+	return codes
+}
+
+// Filter returns members of err containing code type C. err is treated as a
+// single member if it is not *Errors
+func Filter[C Code](err error) []error {
+	members := []error{err}
+	if x, ok := err.(*Errors); ok {
+		members = x.errs
+	}
+	var errs []error
+	for _, m := range members {
+		if m != nil && Is[C](m) {
+			errs = append(errs, m)
+		}
+	}
+	return errs
+}
+
+func (e *coderr[C]) value() any {
+	return e.code
+}
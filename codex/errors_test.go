@@ -0,0 +1,98 @@
+package codex_test
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	. "github.com/xoctopus/x/codex"
+	"github.com/xoctopus/x/misc/cleanup"
+	. "github.com/xoctopus/x/testx"
+)
+
+func ExampleJoin() {
+	var errs Errors
+	errs.Append(nil)
+	fmt.Println(errs.Err())
+
+	errs.Append(
+		New(ECODE__REASON1),
+		fmt.Errorf("field name: %w", New(ORDER_CODE__NOT_FOUND)),
+		Join(New(ECODE__REASON2), New(ECODE__REASON1)),
+	)
+	err := errs.Err()
+	fmt.Println(err)
+	fmt.Println(errs.Codes())
+	fmt.Println(CodesOf[ECode](err))
+	fmt.Println(IsCode(err, ORDER_CODE__NOT_FOUND))
+
+	// Output:
+	// <nil>
+	// [region:2] reason1
+	// field name: order code 1
+	// [region:3] reason2
+	// [region:2] reason1
+	// [2 1 3]
+	// [2 3]
+	// true
+}
+
+func TestErrors(t *testing.T) {
+	Expect(t, Join(), BeNil[error]())
+	Expect(t, Join(nil, nil), BeNil[error]())
+	Expect(t, (*Errors)(nil).Err(), BeNil[error]())
+
+	e1 := With(New(ECODE__REASON1), "k", 1)
+	e2 := errors.New("plain")
+	e3 := Wrap(ORDER_CODE__PAID, New(ECODE__REASON2))
+	err := Join(e1, Join(e2, nil), e3)
+
+	list, ok := errors.AsType[*Errors](err)
+	Expect(t, ok, BeTrue())
+	Expect(t, list.Len(), Equal(3))
+	Expect(t, list.Unwrap(), Equal([]error{e1, e2, e3}))
+
+	Expect(t, err, IsError(e2))
+	Expect(t, err, IsCodeError(ECODE__REASON2))
+	Expect(t, err, Not(IsCodeError(ORDER_CODE__NOT_FOUND)))
+	Expect(t, CodesOf[ECode](err), Equal([]ECode{ECODE__REASON1, ECODE__REASON2}))
+	Expect(t, CodesOf[OrderCode](err), Equal([]OrderCode{ORDER_CODE__PAID}))
+
+	Expect(t, Filter[ECode](err), Equal([]error{e1, e3}))
+	Expect(t, Filter[OrderCode](err), Equal([]error{e3}))
+	Expect(t, Filter[ECode](e1), Equal([]error{e1}))
+	Expect(t, Filter[ECode](nil), HaveLen[[]error](0))
+
+	Expect(t, fmt.Sprintf("%v", err), Equal(err.Error()))
+	Expect(t, fmt.Sprintf("%+v", err), Equal(
+		"[region:2] reason1 [k=1]\nplain\norder code 2. [cause: [region:3] reason2]",
+	))
+	Expect(t, slog.AnyValue(err).Resolve().String(), Equal(
+		"[0=[region:2] reason1 1=plain 2=order code 2. [cause: [region:3] reason2]]",
+	))
+
+	t.Run("Wrapped", func(t *testing.T) {
+		CaptureStack(true)
+		defer CaptureStack(false)
+
+		cause := Join(With(New(ECODE__REASON1), "k", 1), errors.New("plain"))
+		err := Wrap(ORDER_CODE__PAID, cause)
+		Expect(t, err.Error(), Equal("order code 2. [cause: [region:2] reason1\nplain]"))
+		Expect(t, Wrapf(ORDER_CODE__PAID, cause, "id %d", 1).Error(), Equal(
+			"order code 2. id 1. [cause: [region:2] reason1\nplain]",
+		))
+		Expect(t, fmt.Sprintf("%+v", err), ContainsSubString("order code 2. [cause: [region:2] reason1\nplain] [k=1]"))
+	})
+	t.Run("Collector", func(t *testing.T) {
+		c := cleanup.NewCollector()
+		c.Collect(func() error { return New(ECODE__REASON1) })
+		c.Collect(func() error { return nil })
+		c.Collect(func() error { return New(ORDER_CODE__PAID) })
+
+		err := Join(New(ECODE__REASON2))
+		Expect(t, c.JoinTo(&err), Failed())
+		Expect(t, err.(*Errors).Len(), Equal(3))
+		Expect(t, CodesOf[ECode](err), Equal([]ECode{ECODE__REASON2, ECODE__REASON1}))
+	})
+}
@@ -0,0 +1,106 @@
+package codex
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+)
+
+// With attaches key/value fields to err. args are converted to slog.Attr as
+// slog.Logger does. the returned error is a copy of err if it is a coded error,
+// otherwise err is wrapped.
+func With(err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	attrs := slog.Group("", args...).Value.Group()
+	if x, ok := err.(interface{ with([]slog.Attr) error }); ok {
+		return x.with(attrs)
+	}
+	return &fielded{error: err, fields: attrs}
+}
+
+// Fields returns fields attached to errors in err's chain, from the outermost
+// to the innermost.
+func Fields(err error) []slog.Attr {
+	var attrs []slog.Attr
+	for _, e := range chain(err) {
+		if x, ok := e.(interface{ fieldList() []slog.Attr }); ok {
+			attrs = append(attrs, x.fieldList()...)
+		}
+	}
+	return attrs
+}
+
+// chain flattens err's tree in pre-order
+func chain(err error) []error {
+	if err == nil {
+		return nil
+	}
+	errs := []error{err}
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		errs = append(errs, chain(x.Unwrap())...)
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			errs = append(errs, chain(e)...)
+		}
+	}
+	return errs
+}
+
+// origin returns the innermost stack in err's chain
+func origin(err error) stack {
+	var s stack
+	for _, e := range chain(err) {
+		if x, ok := e.(interface{ stackTrace() stack }); ok && len(x.stackTrace()) > 0 {
+			s = x.stackTrace()
+		}
+	}
+	return s
+}
+
+// format implements fmt.Formatter for errors in this package. `%+v` prints
+// fields in chain and the stack where the innermost error created.
+func format(err error, s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, err.Error())
+			if attrs := Fields(err); len(attrs) > 0 {
+				_, _ = fmt.Fprintf(s, " %v", attrs)
+			}
+			origin(err).write(s)
+			return
+		}
+		_, _ = io.WriteString(s, err.Error())
+	case 's':
+		_, _ = io.WriteString(s, err.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", err.Error())
+	}
+}
+
+func logValue(err error, attrs ...slog.Attr) slog.Value {
+	attrs = append(attrs, slog.String("error", err.Error()))
+	return slog.GroupValue(append(attrs, Fields(err)...)...)
+}
+
+// fielded attaches fields to an error without code
+type fielded struct {
+	error
+	fields []slog.Attr
+}
+
+func (e *fielded) Unwrap() error { return e.error }
+
+func (e *fielded) with(attrs []slog.Attr) error {
+	return &fielded{error: e.error, fields: append(slices.Clip(e.fields), attrs...)}
+}
+
+func (e *fielded) fieldList() []slog.Attr { return e.fields }
+
+func (e *fielded) Format(s fmt.State, verb rune) { format(e, s, verb) }
+
+func (e *fielded) LogValue() slog.Value { return logValue(e) }
@@ -0,0 +1,98 @@
+package codex_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	. "github.com/xoctopus/x/codex"
+	. "github.com/xoctopus/x/testx"
+)
+
+func ExampleWith() {
+	err := With(New(ECODE__REASON1), "userID", 100)
+	err = fmt.Errorf("handling order: %w", With(Wrap(ECODE__REASON2, err), "orderID", "o-1"))
+
+	logger := slog.New(slog.NewTextHandler(&stdout{}, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Error("failed", "err", errors.Unwrap(err))
+	fmt.Println(Fields(err))
+
+	// Output:
+	// level=ERROR msg=failed err.code=3 err.error="[region:3] reason2. [cause: [region:2] reason1]" err.orderID=o-1 err.userID=100
+	// [orderID=o-1 userID=100]
+}
+
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) {
+	fmt.Print(string(p))
+	return len(p), nil
+}
+
+func TestWith(t *testing.T) {
+	Expect(t, With(nil, "k", "v"), BeNil[error]())
+
+	base := New(ECODE__REASON1)
+	err := With(base, "k1", 1, slog.String("k2", "2"))
+	Expect(t, err.Error(), Equal(base.Error()))
+	Expect(t, err, IsCodeError(ECODE__REASON1))
+	Expect(t, Fields(base), HaveLen[[]slog.Attr](0))
+	Expect(t, fmt.Sprint(Fields(err)), Equal("[k1=1 k2=2]"))
+
+	err2 := With(err, "k3", 3)
+	Expect(t, fmt.Sprint(Fields(err)), Equal("[k1=1 k2=2]"))
+	Expect(t, fmt.Sprint(Fields(err2)), Equal("[k1=1 k2=2 k3=3]"))
+
+	t.Run("WithoutCode", func(t *testing.T) {
+		cause := errors.New("any")
+		err := With(With(cause, "k1", 1), "k2", 2)
+		Expect(t, err, IsError(cause))
+		Expect(t, err.Error(), Equal("any"))
+		Expect(t, fmt.Sprint(Fields(err)), Equal("[k1=1 k2=2]"))
+		Expect(t, fmt.Sprintf("%+v", err), Equal("any [k1=1 k2=2]"))
+		Expect(t, slog.AnyValue(err).Resolve().String(), Equal("[error=any k1=1 k2=2]"))
+	})
+	t.Run("Joined", func(t *testing.T) {
+		err := errors.Join(With(New(ECODE__REASON1), "k1", 1), With(New(ECODE__REASON2), "k2", 2))
+		Expect(t, fmt.Sprint(Fields(err)), Equal("[k1=1 k2=2]"))
+	})
+}
+
+func TestFormat(t *testing.T) {
+	err := With(Wrap(ECODE__REASON2, With(New(ECODE__REASON1), "k1", 1)), "k2", 2)
+	msg := "[region:3] reason2. [cause: [region:2] reason1]"
+
+	Expect(t, fmt.Sprintf("%v", err), Equal(msg))
+	Expect(t, fmt.Sprintf("%s", err), Equal(msg))
+	Expect(t, fmt.Sprintf("%q", err), Equal(fmt.Sprintf("%q", msg)))
+	Expect(t, fmt.Sprintf("%+v", err), Equal(msg+" [k2=2 k1=1]"))
+
+	t.Run("CaptureStack", func(t *testing.T) {
+		CaptureStack(true)
+		defer CaptureStack(false)
+
+		inner := New(ECODE__REASON1)
+		err := Wrapf(ECODE__REASON2, fmt.Errorf("wrapped: %w", inner), "message")
+		Expect(t, err.Error(), Equal("[region:3] reason2. message. [cause: wrapped: [region:2] reason1]"))
+
+		detail := fmt.Sprintf("%+v", err)
+		lines := strings.Split(detail, "\n")
+		Expect(t, lines[0], Equal(err.Error()))
+		Expect(t, lines[1], HaveSuffix("codex_test.TestFormat.func1"))
+		Expect(t, lines[2], ContainsSubString("fields_test.go"))
+
+		buf := bytes.NewBuffer(nil)
+		_, _ = fmt.Fprintf(buf, "%+v", Errorf(ECODE__REASON1, "x"))
+		Expect(t, buf.String(), ContainsSubString("fields_test.go"))
+	})
+}
@@ -0,0 +1,91 @@
+package codex
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/xoctopus/x/contextx"
+	"github.com/xoctopus/x/syncx"
+)
+
+type tCtxLanguage struct{}
+
+var (
+	// WithLanguage carries language tag, such as `en` or `zh-CN`, in context
+	WithLanguage  = contextx.With[tCtxLanguage, string]
+	LanguageFrom  = contextx.From[tCtxLanguage, string]
+	CarryLanguage = contextx.Carry[tCtxLanguage, string]
+)
+
+type locale struct {
+	typ  reflect.Type
+	lang string
+}
+
+var locales = syncx.NewXmap[locale, func(any) (string, bool)]()
+
+// RegisterMessages registers message templates of code type C in language
+// lang. `{key}` in template is replaced by the field value of key attached to
+// the error. registering the same language again overrides the previous one.
+func RegisterMessages[C Code](lang string, templates map[C]string) {
+	locales.Store(
+		locale{typ: reflect.TypeFor[C](), lang: strings.ToLower(lang)},
+		func(v any) (string, bool) {
+			tmpl, ok := templates[v.(C)]
+			return tmpl, ok
+		},
+	)
+}
+
+// Localize returns the user-facing message of err in the language carried by
+// ctx. it uses the outermost coded error in err's chain and falls back to the
+// default message of its code, or err.Error() if err has no code. a language
+// tag like `zh-Hant-TW` is matched in order of `zh-hant-tw`, `zh-hant`, `zh`.
+func Localize(ctx context.Context, err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, e := range chain(err) {
+		x, ok := e.(interface{ template(string) string })
+		if !ok {
+			continue
+		}
+		lang, _ := LanguageFrom(ctx)
+		tmpl := x.template(strings.ToLower(lang))
+
+		attrs := Fields(err)
+		if len(attrs) == 0 {
+			return tmpl
+		}
+		pairs := make([]string, 0, len(attrs)*2)
+		for _, a := range attrs {
+			pairs = append(pairs, "{"+a.Key+"}", a.Value.String())
+		}
+		return strings.NewReplacer(pairs...).Replace(tmpl)
+	}
+	return err.Error()
+}
+
+// template returns localized message template of code in lang or its default
+// message
+func (e *coderr[C]) template(lang string) string {
+	t := reflect.TypeFor[C]()
+	for lang != "" {
+		if f, ok := locales.Load(locale{typ: t, lang: lang}); ok {
+			if tmpl, ok := f(e.code); ok {
+				return tmpl
+			}
+		}
+		i := strings.LastIndexByte(lang, '-')
+		if i < 0 {
+			break
+		}
+		lang = lang[:i]
+	}
+	if x, ok := any(e.code).(interface{ Message() string }); ok {
+		return x.Message()
+	}
+	return fmt.Sprintf("%T[%d]", e.code, e.code)
+}
@@ -0,0 +1,57 @@
+package codex_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/xoctopus/x/codex"
+	. "github.com/xoctopus/x/testx"
+)
+
+func init() {
+	RegisterMessages("zh", map[OrderCode]string{
+		ORDER_CODE__NOT_FOUND: "订单 {orderID} 不存在",
+		ORDER_CODE__PAID:      "订单已支付",
+	})
+	RegisterMessages("zh-Hant", map[OrderCode]string{
+		ORDER_CODE__NOT_FOUND: "訂單 {orderID} 不存在",
+	})
+}
+
+func ExampleLocalize() {
+	err := With(Wrap(ORDER_CODE__NOT_FOUND, errors.New("no rows")), "orderID", "o-1")
+
+	fmt.Println(Localize(context.Background(), err))
+	fmt.Println(Localize(WithLanguage(context.Background(), "zh-CN"), err))
+	fmt.Println(Localize(WithLanguage(context.Background(), "zh-Hant-TW"), err))
+
+	// Output:
+	// order code 1
+	// 订单 o-1 不存在
+	// 訂單 o-1 不存在
+}
+
+func TestLocalize(t *testing.T) {
+	zh := WithLanguage(context.Background(), "zh")
+	hant := CarryLanguage("zh-Hant")(context.Background())
+
+	Expect(t, Localize(zh, nil), Equal(""))
+	Expect(t, Localize(zh, errors.New("any")), Equal("any"))
+
+	// fallback from zh-Hant to zh
+	Expect(t, Localize(hant, New(ORDER_CODE__PAID)), Equal("订单已支付"))
+	// fallback to default message
+	Expect(t, Localize(zh, New(ORDER_CODE__EXPIRED)), Equal("order code 3"))
+	Expect(t, Localize(WithLanguage(context.Background(), "en"), New(ORDER_CODE__PAID)), Equal("order code 2"))
+	Expect(t, Localize(zh, New(ECode3(1))), Equal("codex_test.ECode3[1]"))
+	// outermost coded error
+	Expect(t, Localize(zh, fmt.Errorf("x: %w", Wrap(ORDER_CODE__PAID, New(ORDER_CODE__NOT_FOUND)))), Equal("订单已支付"))
+	// placeholder without field
+	Expect(t, Localize(zh, New(ORDER_CODE__NOT_FOUND)), Equal("订单 {orderID} 不存在"))
+
+	lang, ok := LanguageFrom(hant)
+	Expect(t, ok, BeTrue())
+	Expect(t, lang, Equal("zh-Hant"))
+}
@@ -0,0 +1,103 @@
+package codex
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/xoctopus/x/syncx"
+)
+
+// kind holds registered metadata of a code type
+type kind struct {
+	// name is the stable type name on wire
+	name string
+	// resolve returns transport status of a code
+	resolve func(any) Meta
+	// decode rebuilds a coded error from wire format
+	decode func(*Payload, error) (error, bool)
+}
+
+var (
+	kinds = syncx.NewXmap[reflect.Type, *kind]()
+	names = syncx.NewXmap[string, *kind]()
+)
+
+// RegisterType registers code type C, so that coded errors with C can be
+// reconstructed from wire format by Decode.
+func RegisterType[C Code]() {
+	register[C](func(*kind) {})
+}
+
+// Register declares transport status of code type C by resolve and registers
+// code type C as RegisterType does. registering a code type again overrides the
+// previous one.
+func Register[C Code](resolve func(C) Meta) {
+	register[C](func(k *kind) {
+		k.resolve = func(v any) Meta {
+			m := resolve(v.(C))
+			if m.Identifier == "" {
+				m.Identifier = fmt.Sprintf("%T[%d]", v, v)
+			}
+			if m.HTTP == 0 {
+				// zero Canonical is OK, an error without status is unknown
+				if m.Canonical == CanonicalOK {
+					m.Canonical = CanonicalUnknown
+				}
+				m.HTTP = m.Canonical.HTTP()
+			}
+			return m
+		}
+	})
+}
+
+func register[C Code](update func(*kind)) {
+	t := reflect.TypeFor[C]()
+	k := &kind{
+		name: nameof(t),
+		decode: func(p *Payload, cause error) (error, bool) {
+			code, ok := parse[C](string(p.Code))
+			if !ok {
+				return nil, false
+			}
+			e := &coderr[C]{code: code, msg: escape(p.Detail), cause: cause}
+			if cause != nil {
+				e.args = []any{cause}
+			}
+			e.fields = p.attrs()
+			return e, true
+		},
+	}
+	if prev, ok := kinds.Load(t); ok {
+		*k = *prev
+	}
+	update(k)
+	kinds.Store(t, k)
+	names.Store(k.name, k)
+}
+
+// MetaOf returns registered transport status of code
+func MetaOf[C Code](code C) (Meta, bool) {
+	if k, ok := kinds.Load(reflect.TypeFor[C]()); ok && k.resolve != nil {
+		return k.resolve(code), true
+	}
+	return Meta{}, false
+}
+
+// parse parses code value of C from decimal string
+func parse[C Code](s string) (C, bool) {
+	if ^C(0) < 0 {
+		v, err := strconv.ParseInt(s, 10, int(reflect.TypeFor[C]().Size())*8)
+		return C(v), err == nil
+	}
+	v, err := strconv.ParseUint(s, 10, int(reflect.TypeFor[C]().Size())*8)
+	return C(v), err == nil
+}
+
+// nameof returns full qualified type name. eg: `github.com/xoctopus/x/textx.Ecode`
+func nameof(t reflect.Type) string {
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
@@ -0,0 +1,43 @@
+package codex
+
+import (
+	"io"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+var capturing atomic.Bool
+
+// CaptureStack enables or disables capturing stack trace when creating coded
+// errors. it is disabled by default to avoid the overhead.
+func CaptureStack(enabled bool) {
+	capturing.Store(enabled)
+}
+
+// stack holds program counters of the frames creating an error
+type stack []uintptr
+
+// callers captures the stack of the caller of the error constructor
+func callers() stack {
+	if !capturing.Load() {
+		return nil
+	}
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+func (s stack) write(w io.Writer) {
+	if len(s) == 0 {
+		return
+	}
+	frames := runtime.CallersFrames(s)
+	for {
+		f, more := frames.Next()
+		_, _ = io.WriteString(w, "\n"+f.Function+"\n\t"+f.File+":"+strconv.Itoa(f.Line))
+		if !more {
+			return
+		}
+	}
+}
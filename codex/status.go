@@ -0,0 +1,109 @@
+package codex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Canonical is gRPC-like canonical status code
+type Canonical uint32
+
+const (
+	CanonicalOK Canonical = iota
+	CanonicalCanceled
+	CanonicalUnknown
+	CanonicalInvalidArgument
+	CanonicalDeadlineExceeded
+	CanonicalNotFound
+	CanonicalAlreadyExists
+	CanonicalPermissionDenied
+	CanonicalResourceExhausted
+	CanonicalFailedPrecondition
+	CanonicalAborted
+	CanonicalOutOfRange
+	CanonicalUnimplemented
+	CanonicalInternal
+	CanonicalUnavailable
+	CanonicalDataLoss
+	CanonicalUnauthenticated
+)
+
+var canonicals = [...]struct {
+	name string
+	http int
+}{
+	CanonicalOK:                 {"OK", http.StatusOK},
+	CanonicalCanceled:           {"CANCELED", 499},
+	CanonicalUnknown:            {"UNKNOWN", http.StatusInternalServerError},
+	CanonicalInvalidArgument:    {"INVALID_ARGUMENT", http.StatusBadRequest},
+	CanonicalDeadlineExceeded:   {"DEADLINE_EXCEEDED", http.StatusGatewayTimeout},
+	CanonicalNotFound:           {"NOT_FOUND", http.StatusNotFound},
+	CanonicalAlreadyExists:      {"ALREADY_EXISTS", http.StatusConflict},
+	CanonicalPermissionDenied:   {"PERMISSION_DENIED", http.StatusForbidden},
+	CanonicalResourceExhausted:  {"RESOURCE_EXHAUSTED", http.StatusTooManyRequests},
+	CanonicalFailedPrecondition: {"FAILED_PRECONDITION", http.StatusBadRequest},
+	CanonicalAborted:            {"ABORTED", http.StatusConflict},
+	CanonicalOutOfRange:         {"OUT_OF_RANGE", http.StatusBadRequest},
+	CanonicalUnimplemented:      {"UNIMPLEMENTED", http.StatusNotImplemented},
+	CanonicalInternal:           {"INTERNAL", http.StatusInternalServerError},
+	CanonicalUnavailable:        {"UNAVAILABLE", http.StatusServiceUnavailable},
+	CanonicalDataLoss:           {"DATA_LOSS", http.StatusInternalServerError},
+	CanonicalUnauthenticated:    {"UNAUTHENTICATED", http.StatusUnauthorized},
+}
+
+func (c Canonical) String() string {
+	if int(c) < len(canonicals) {
+		return canonicals[c].name
+	}
+	return fmt.Sprintf("CANONICAL(%d)", uint32(c))
+}
+
+// HTTP returns the http status conventionally mapped from c
+func (c Canonical) HTTP() int {
+	if int(c) < len(canonicals) {
+		return canonicals[c].http
+	}
+	return http.StatusInternalServerError
+}
+
+// Meta describes transport status of a code
+type Meta struct {
+	// Identifier is a stable string identifier of code. eg: `ORDER_NOT_FOUND`
+	Identifier string
+	// HTTP status. Canonical.HTTP() is used if it is zero.
+	HTTP int
+	// Canonical is gRPC-like canonical status. if both HTTP and Canonical are
+	// unset, it is CanonicalUnknown with http 500.
+	Canonical Canonical
+}
+
+func (e *coderr[C]) meta() (Meta, bool) {
+	return MetaOf(e.code)
+}
+
+// StatusOf walks err's chain and returns the transport status of the outermost
+// coded error whose code type is registered. context errors are mapped to
+// CANCELED and DEADLINE_EXCEEDED, nil to OK and others to UNKNOWN.
+func StatusOf(err error) Meta {
+	if err == nil {
+		return Meta{Identifier: CanonicalOK.String(), HTTP: http.StatusOK}
+	}
+	for _, e := range chain(err) {
+		if x, ok := e.(interface{ meta() (Meta, bool) }); ok {
+			if m, ok := x.meta(); ok {
+				return m
+			}
+		}
+	}
+
+	c := CanonicalUnknown
+	switch {
+	case errors.Is(err, context.Canceled):
+		c = CanonicalCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		c = CanonicalDeadlineExceeded
+	}
+	return Meta{Identifier: c.String(), HTTP: c.HTTP(), Canonical: c}
+}
@@ -0,0 +1,95 @@
+package codex_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/xoctopus/x/codex"
+	. "github.com/xoctopus/x/testx"
+)
+
+type OrderCode uint16
+
+const (
+	ORDER_CODE__NOT_FOUND OrderCode = iota + 1
+	ORDER_CODE__PAID
+	ORDER_CODE__EXPIRED
+)
+
+func (c OrderCode) Message() string { return fmt.Sprintf("order code %d", c) }
+
+func init() {
+	Register(func(c OrderCode) Meta {
+		switch c {
+		case ORDER_CODE__NOT_FOUND:
+			return Meta{Identifier: "ORDER_NOT_FOUND", Canonical: CanonicalNotFound}
+		case ORDER_CODE__PAID:
+			return Meta{Identifier: "ORDER_PAID", Canonical: CanonicalFailedPrecondition, HTTP: http.StatusConflict}
+		default:
+			return Meta{Canonical: CanonicalInternal}
+		}
+	})
+}
+
+type BareCode int8
+
+func init() {
+	Register(func(BareCode) Meta { return Meta{Identifier: "BARE"} })
+}
+
+func ExampleStatusOf() {
+	err := fmt.Errorf("query: %w", Wrap(ORDER_CODE__NOT_FOUND, errors.New("no rows")))
+	m := StatusOf(err)
+	fmt.Println(m.Identifier, m.HTTP, m.Canonical)
+
+	// Output:
+	// ORDER_NOT_FOUND 404 NOT_FOUND
+}
+
+func TestStatusOf(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		meta Meta
+	}{
+		{"Nil", nil, Meta{Identifier: "OK", HTTP: 200, Canonical: CanonicalOK}},
+		{"Unknown", errors.New("any"), Meta{Identifier: "UNKNOWN", HTTP: 500, Canonical: CanonicalUnknown}},
+		{"Canceled", fmt.Errorf("x: %w", context.Canceled), Meta{Identifier: "CANCELED", HTTP: 499, Canonical: CanonicalCanceled}},
+		{"Deadline", context.DeadlineExceeded, Meta{Identifier: "DEADLINE_EXCEEDED", HTTP: 504, Canonical: CanonicalDeadlineExceeded}},
+		{"Unregistered", New(ECODE__REASON1), Meta{Identifier: "UNKNOWN", HTTP: 500, Canonical: CanonicalUnknown}},
+		{"HTTPOverride", New(ORDER_CODE__PAID), Meta{Identifier: "ORDER_PAID", HTTP: 409, Canonical: CanonicalFailedPrecondition}},
+		{"DefaultIdentifier", New(ORDER_CODE__EXPIRED), Meta{Identifier: "codex_test.OrderCode[3]", HTTP: 500, Canonical: CanonicalInternal}},
+		{"Unset", New(BareCode(1)), Meta{Identifier: "BARE", HTTP: 500, Canonical: CanonicalUnknown}},
+		{
+			"Outermost",
+			Wrap(ECODE__REASON1, Wrap(ORDER_CODE__PAID, New(ORDER_CODE__NOT_FOUND))),
+			Meta{Identifier: "ORDER_PAID", HTTP: 409, Canonical: CanonicalFailedPrecondition},
+		},
+		{
+			"Joined",
+			errors.Join(errors.New("any"), New(ORDER_CODE__NOT_FOUND)),
+			Meta{Identifier: "ORDER_NOT_FOUND", HTTP: 404, Canonical: CanonicalNotFound},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			Expect(t, StatusOf(c.err), Equal(c.meta))
+		})
+	}
+
+	_, ok := MetaOf(ECODE__REASON1)
+	Expect(t, ok, BeFalse())
+	m, ok := MetaOf(ORDER_CODE__NOT_FOUND)
+	Expect(t, ok, BeTrue())
+	Expect(t, m.HTTP, Equal(http.StatusNotFound))
+}
+
+func TestCanonical(t *testing.T) {
+	Expect(t, CanonicalUnauthenticated.String(), Equal("UNAUTHENTICATED"))
+	Expect(t, CanonicalUnauthenticated.HTTP(), Equal(http.StatusUnauthorized))
+	Expect(t, Canonical(100).String(), Equal("CANONICAL(100)"))
+	Expect(t, Canonical(100).HTTP(), Equal(http.StatusInternalServerError))
+}
@@ -0,0 +1,224 @@
+package codex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Payload is the wire format of an error chain
+type Payload struct {
+	// Type is the full qualified name of code type
+	Type string `json:"type,omitempty"`
+	// Code is the decimal code value, kept as json.Number to preserve 64-bit codes
+	Code json.Number `json:"code,omitempty"`
+	// Message is the error message
+	Message string `json:"message"`
+	// Detail is the formatted user message of coded error
+	Detail string `json:"detail,omitempty"`
+	// Fields are key/value fields attached to error
+	Fields map[string]any `json:"fields,omitempty"`
+	// Cause is the wrapped error
+	Cause *Payload `json:"cause,omitempty"`
+	// Causes are the wrapped errors, such as errors.Join
+	Causes []*Payload `json:"causes,omitempty"`
+}
+
+func (p *Payload) attrs() []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(p.Fields))
+	for _, k := range slices.Sorted(maps.Keys(p.Fields)) {
+		attrs = append(attrs, slog.Any(k, p.Fields[k]))
+	}
+	return attrs
+}
+
+// Encode converts err's chain to wire format
+func Encode(err error) *Payload {
+	if err == nil {
+		return nil
+	}
+
+	var p *Payload
+	switch x := err.(type) {
+	case interface{ payload() *Payload }:
+		p = x.payload()
+	case interface{ fieldList() []slog.Attr }:
+		p = &Payload{Message: err.Error(), Fields: mapping(x.fieldList())}
+	default:
+		p = &Payload{Message: err.Error()}
+	}
+
+	if p.Cause != nil || len(p.Causes) > 0 {
+		return p
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		p.Cause = Encode(x.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			if e != nil {
+				p.Causes = append(p.Causes, Encode(e))
+			}
+		}
+	}
+	return p
+}
+
+// Decode rebuilds an error from wire format. coded errors with registered code
+// types are reconstructed, so IsCode and As work as local ones. the others are
+// degraded to *RemoteError.
+func Decode(p *Payload) error {
+	if p == nil {
+		return nil
+	}
+
+	cause := Decode(p.Cause)
+	if p.Type != "" && len(p.Causes) == 0 {
+		if k, ok := names.Load(p.Type); ok {
+			if err, ok := k.decode(p, cause); ok {
+				return err
+			}
+		}
+	}
+
+	e := &RemoteError{
+		Type:    p.Type,
+		Code:    p.Code,
+		Message: p.Message,
+		Detail:  p.Detail,
+		fields:  p.attrs(),
+	}
+	if cause != nil {
+		e.causes = append(e.causes, cause)
+	}
+	e.joined = len(p.Causes) > 0
+	for _, c := range p.Causes {
+		e.causes = append(e.causes, Decode(c))
+	}
+	return e
+}
+
+// Marshal encodes err's chain to JSON
+func Marshal(err error) ([]byte, error) {
+	return json.Marshal(Encode(err))
+}
+
+// Unmarshal decodes JSON data encoded by Marshal and stores the rebuilt error
+// in dst
+func Unmarshal(data []byte, dst *error) error {
+	var p *Payload
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&p); err != nil {
+		return err
+	}
+	*dst = Decode(p)
+	return nil
+}
+
+// RemoteError is an error rebuilt from wire format whose code type is unknown
+// or which has no code.
+type RemoteError struct {
+	Type    string
+	Code    json.Number
+	Message string
+	Detail  string
+
+	fields []slog.Attr
+	causes []error
+	joined bool
+}
+
+func (e *RemoteError) Error() string {
+	return e.Message
+}
+
+func (e *RemoteError) Unwrap() []error {
+	return e.causes
+}
+
+func (e *RemoteError) fieldList() []slog.Attr {
+	return e.fields
+}
+
+func (e *RemoteError) payload() *Payload {
+	p := &Payload{
+		Type:    e.Type,
+		Code:    e.Code,
+		Message: e.Message,
+		Detail:  e.Detail,
+		Fields:  mapping(e.fields),
+	}
+	if !e.joined && len(e.causes) == 1 {
+		p.Cause = Encode(e.causes[0])
+		return p
+	}
+	for _, c := range e.causes {
+		p.Causes = append(p.Causes, Encode(c))
+	}
+	return p
+}
+
+func (e *RemoteError) Format(s fmt.State, verb rune) {
+	format(e, s, verb)
+}
+
+func (e *RemoteError) LogValue() slog.Value {
+	if e.Type == "" {
+		return logValue(e)
+	}
+	return logValue(e, slog.String("type", e.Type), slog.Any("code", e.Code))
+}
+
+func (e *coderr[C]) payload() *Payload {
+	return &Payload{
+		Type:    nameof(reflect.TypeFor[C]()),
+		Code:    json.Number(fmt.Sprintf("%d", e.code)),
+		Message: e.Error(),
+		Detail:  e.detail(),
+		Fields:  mapping(e.fields),
+	}
+}
+
+// detail returns formatted user message without cause
+func (e *coderr[C]) detail() string {
+	if len(e.msg) == 0 {
+		return ""
+	}
+	args := e.args
+	if e.cause != nil {
+		args = args[:len(args)-1]
+	}
+	return fmt.Sprintf(e.msg, args...)
+}
+
+// escape escapes s to be used as a format string
+func escape(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// mapping converts attrs to a map for wire format
+func mapping(attrs []slog.Attr) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		m[a.Key] = valueof(a.Value)
+	}
+	return m
+}
+
+func valueof(v slog.Value) any {
+	v = v.Resolve()
+	if v.Kind() == slog.KindGroup {
+		return mapping(v.Group())
+	}
+	return v.Any()
+}
@@ -0,0 +1,103 @@
+package codex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	. "github.com/xoctopus/x/codex"
+	. "github.com/xoctopus/x/testx"
+)
+
+type RemoteCode int32
+
+func init() {
+	RegisterType[ECode]()
+}
+
+func ExampleMarshal() {
+	err := With(Wrapf(ORDER_CODE__PAID, errors.New("tx committed"), "order %s", "o-1"), "userID", 100)
+
+	data, _ := Marshal(err)
+	fmt.Println(string(data))
+
+	var decoded error
+	_ = Unmarshal(data, &decoded)
+	fmt.Println(decoded)
+	fmt.Println(IsCode(decoded, ORDER_CODE__PAID))
+
+	// Output:
+	// {"type":"github.com/xoctopus/x/codex_test.OrderCode","code":2,"message":"order code 2. order o-1. [cause: tx committed]","detail":"order o-1","fields":{"userID":100},"cause":{"message":"tx committed"}}
+	// order code 2. order o-1. [cause: tx committed]
+	// true
+}
+
+func TestMarshal(t *testing.T) {
+	roundtrip := func(t *testing.T, err error) error {
+		data, e := Marshal(err)
+		Expect(t, e, Succeed())
+
+		var decoded error
+		Expect(t, Unmarshal(data, &decoded), Succeed())
+		return decoded
+	}
+
+	t.Run("Nil", func(t *testing.T) {
+		Expect(t, roundtrip(t, nil), BeNil[error]())
+	})
+	t.Run("Chain", func(t *testing.T) {
+		err := Wrapf(
+			ECODE__REASON2,
+			fmt.Errorf("wrapped: %w", With(Errorf(ORDER_CODE__NOT_FOUND, "100%% %s", "sure"), "k", "v")),
+			"user message: %d", 1,
+		)
+		decoded := roundtrip(t, err)
+		Expect(t, decoded.Error(), Equal(err.Error()))
+		Expect(t, decoded, IsCodeError(ECODE__REASON2))
+		Expect(t, decoded, IsCodeError(ORDER_CODE__NOT_FOUND))
+		Expect(t, fmt.Sprint(Fields(decoded)), Equal("[k=v]"))
+
+		inner, ok := As[OrderCode](decoded)
+		Expect(t, ok, BeTrue())
+		Expect(t, inner.Error(), Equal("order code 1. 100% sure"))
+	})
+	t.Run("UnknownType", func(t *testing.T) {
+		err := With(Wrap(RemoteCode(10), errors.New("cause")), "k", 1)
+		data, _ := Marshal(err)
+
+		var decoded error
+		Expect(t, Unmarshal(data, &decoded), Succeed())
+		Expect(t, Is[RemoteCode](decoded), BeFalse())
+		Expect(t, decoded.Error(), Equal(err.Error()))
+
+		remote, ok := errors.AsType[*RemoteError](decoded)
+		Expect(t, ok, BeTrue())
+		Expect(t, remote.Type, Equal("github.com/xoctopus/x/codex_test.RemoteCode"))
+		Expect(t, remote.Code, Equal(json.Number("10")))
+		Expect(t, slog.AnyValue(remote).Resolve().String(), Equal(
+			"[type=github.com/xoctopus/x/codex_test.RemoteCode code=10 error=codex_test.RemoteCode[10]. [cause: cause] k=1]",
+		))
+
+		again, _ := Marshal(decoded)
+		Expect(t, string(again), Equal(string(data)))
+	})
+	t.Run("Joined", func(t *testing.T) {
+		err := errors.Join(New(ECODE__REASON1), errors.New("any"))
+		decoded := roundtrip(t, err)
+		Expect(t, decoded.Error(), Equal(err.Error()))
+		Expect(t, decoded, IsCodeError(ECODE__REASON1))
+	})
+	t.Run("CodeOutOfRange", func(t *testing.T) {
+		var decoded error
+		data := `{"type":"github.com/xoctopus/x/codex_test.ECode","code":300,"message":"overflow"}`
+		Expect(t, Unmarshal([]byte(data), &decoded), Succeed())
+		Expect(t, Is[ECode](decoded), BeFalse())
+		Expect(t, decoded.Error(), Equal("overflow"))
+	})
+	t.Run("InvalidData", func(t *testing.T) {
+		var decoded error
+		Expect(t, Unmarshal([]byte("{"), &decoded), Failed())
+	})
+}
@@ -0,0 +1,129 @@
+package contextx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xoctopus/x/misc/must"
+)
+
+type tCtxBag struct{}
+
+// WithBag returns a context carries a new Bag with the Bag. the Bag is
+// request-scoped, so that downstream handlers can report data back upstream
+// by slots. a nested WithBag starts a new scope and hides the outer one.
+func WithBag(ctx context.Context) (context.Context, *Bag) {
+	b := &Bag{entries: map[any]entry{}}
+	return WithValue(ctx, tCtxBag{}, b), b
+}
+
+// BagFrom returns the Bag carried by ctx
+func BagFrom(ctx context.Context) (*Bag, bool) {
+	b, ok := ctx.Value(tCtxBag{}).(*Bag)
+	return b, ok
+}
+
+// Bag is a mutable storage of slot values which is safe for concurrent use
+type Bag struct {
+	mu      sync.Mutex
+	entries map[any]entry
+}
+
+type entry struct {
+	name string
+	v    any
+}
+
+// Snapshot returns a copy of stored values keyed by slot name
+func (b *Bag) Snapshot() map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	m := make(map[string]any, len(b.entries))
+	for _, e := range b.entries {
+		m[e.name] = e.v
+	}
+	return m
+}
+
+// Len returns the count of stored values
+func (b *Bag) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.entries)
+}
+
+// NewSlot creates a typed key of Bag. name identifies the slot in snapshot
+// and should be unique.
+func NewSlot[T any](name string) *Slot[T] {
+	return &Slot[T]{name: name}
+}
+
+// Slot is a typed key of value stored in the Bag carried by context
+type Slot[T any] struct {
+	name string
+}
+
+func (s *Slot[T]) Name() string {
+	return s.name
+}
+
+// From returns the value of s in the Bag carried by ctx
+func (s *Slot[T]) From(ctx context.Context) (T, bool) {
+	if b, ok := BagFrom(ctx); ok {
+		b.mu.Lock()
+		e, ok := b.entries[s]
+		b.mu.Unlock()
+		if ok {
+			v, _ := e.v.(T)
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func (s *Slot[T]) MustFrom(ctx context.Context) T {
+	v, ok := s.From(ctx)
+	must.BeTrueF(ok, "%s not found in context", s.name)
+	return v
+}
+
+// Store stores v of s into the Bag carried by ctx. it returns false if ctx
+// carries no Bag.
+func (s *Slot[T]) Store(ctx context.Context, v T) bool {
+	_, ok := s.Update(ctx, func(T) T { return v })
+	return ok
+}
+
+// Update atomically replaces the value of s in the Bag carried by ctx by the
+// result of f with the current value, or zero value if not stored. it returns
+// the new value and false if ctx carries no Bag. f is called with the Bag
+// locked, so it must not access the Bag.
+func (s *Slot[T]) Update(ctx context.Context, f func(T) T) (T, bool) {
+	b, ok := BagFrom(ctx)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var v T
+	if e, ok := b.entries[s]; ok {
+		v, _ = e.v.(T)
+	}
+	v = f(v)
+	b.entries[s] = entry{name: s.name, v: v}
+	return v, true
+}
+
+// Delete deletes the value of s from the Bag carried by ctx
+func (s *Slot[T]) Delete(ctx context.Context) {
+	if b, ok := BagFrom(ctx); ok {
+		b.mu.Lock()
+		delete(b.entries, s)
+		b.mu.Unlock()
+	}
+}
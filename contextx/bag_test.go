@@ -0,0 +1,94 @@
+package contextx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/xoctopus/x/contextx"
+	. "github.com/xoctopus/x/testx"
+)
+
+var (
+	queries = contextx.NewSlot[int]("queries")
+	tables  = contextx.NewSlot[[]string]("tables")
+)
+
+func query(ctx context.Context, table string) {
+	queries.Update(ctx, func(n int) int { return n + 1 })
+	tables.Update(ctx, func(ts []string) []string { return append(ts, table) })
+}
+
+func ExampleWithBag() {
+	ctx, bag := contextx.WithBag(context.Background())
+
+	query(ctx, "user")
+	query(ctx, "order")
+
+	fmt.Println(queries.MustFrom(ctx))
+	fmt.Println(bag.Snapshot())
+
+	// Output:
+	// 2
+	// map[queries:2 tables:[user order]]
+}
+
+func TestBag(t *testing.T) {
+	t.Run("NoBag", func(t *testing.T) {
+		ctx := context.Background()
+		Expect(t, queries.Store(ctx, 1), BeFalse())
+		_, ok := queries.Update(ctx, func(n int) int { return n + 1 })
+		Expect(t, ok, BeFalse())
+		_, ok = queries.From(ctx)
+		Expect(t, ok, BeFalse())
+		ExpectPanic[error](t, func() { queries.MustFrom(ctx) })
+		queries.Delete(ctx)
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		ctx, bag := contextx.WithBag(context.Background())
+		wg := sync.WaitGroup{}
+		for range 100 {
+			wg.Go(func() { queries.Update(ctx, func(n int) int { return n + 1 }) })
+		}
+		wg.Wait()
+		Expect(t, queries.MustFrom(ctx), Equal(100))
+		Expect(t, bag.Len(), Equal(1))
+
+		Expect(t, queries.Store(ctx, 0), BeTrue())
+		Expect(t, queries.MustFrom(ctx), Equal(0))
+		queries.Delete(ctx)
+		Expect(t, bag.Len(), Equal(0))
+	})
+	t.Run("InterfaceSlot", func(t *testing.T) {
+		failure := contextx.NewSlot[error]("error")
+		ctx, bag := contextx.WithBag(context.Background())
+
+		Expect(t, failure.Store(ctx, nil), BeTrue())
+		err, ok := failure.From(ctx)
+		Expect(t, ok, BeTrue())
+		Expect(t, err, BeNil[error]())
+
+		err, ok = failure.Update(ctx, func(err error) error {
+			if err == nil {
+				return errors.New("failed")
+			}
+			return err
+		})
+		Expect(t, ok, BeTrue())
+		Expect(t, err, ErrorContains("failed"))
+		Expect(t, bag.Snapshot()["error"], Equal[any](err))
+	})
+	t.Run("Scoped", func(t *testing.T) {
+		outer, bag := contextx.WithBag(context.Background())
+		inner, _ := contextx.WithBag(outer)
+		queries.Store(inner, 1)
+		Expect(t, bag.Snapshot(), HaveLen[map[string]any](0))
+
+		snapshot := bag.Snapshot()
+		queries.Store(outer, 1)
+		Expect(t, snapshot, HaveLen[map[string]any](0))
+		Expect(t, queries.Name(), Equal("queries"))
+	})
+}
@@ -0,0 +1,129 @@
+package contextx
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// indexed is the minimum entries of batch indexed by map, batches smaller than
+// it are scanned linearly which is faster for a few keys.
+const indexed = 8
+
+// WithBatch applies carriers to parent and flattens all values they carry into
+// one layer, so that values are found without walking each layer. if carriers
+// derive any layer other than values, such as a cancelable context, the
+// composed context is returned as it is. building a batch costs more than
+// Compose, so it suits contexts carrying many values and looked up frequently.
+func WithBatch(parent context.Context, carriers ...Carrier) context.Context {
+	if parent == nil {
+		panic("parent is nil")
+	}
+	ctx := Compose(carriers...)(parent)
+
+	b := &batch{Context: parent}
+	for c := ctx; !same(c, parent); {
+		switch x := c.(type) {
+		case *kv:
+			b.add(x.k, x.v)
+			c = x.Context
+		case *batch:
+			for i := range x.keys {
+				b.add(x.keys[i], x.vals[i])
+			}
+			c = x.Context
+		case nil:
+			return ctx // parent is replaced
+		default:
+			l := Layer{Type: reflect.TypeOf(c).String()}
+			p := unwrap(c, &l)
+			if !l.HasValue() {
+				return ctx
+			}
+			b.add(l.Key, l.Value)
+			c = p
+		}
+	}
+
+	if len(b.keys) == 0 {
+		return parent
+	}
+	b.index()
+	return b
+}
+
+// Batch returns a Carrier flattens values carried by carriers as WithBatch
+func Batch(carriers ...Carrier) Carrier {
+	return func(ctx context.Context) context.Context {
+		return WithBatch(ctx, carriers...)
+	}
+}
+
+// batch carries multiple values in one layer
+type batch struct {
+	context.Context
+	keys []any
+	vals []any
+	idx  map[any]int
+}
+
+// add appends k and v if k is not carried by outer layers
+func (c *batch) add(k, v any) {
+	for _, x := range c.keys {
+		if x == k {
+			return
+		}
+	}
+	c.keys = append(c.keys, k)
+	c.vals = append(c.vals, v)
+}
+
+func (c *batch) index() {
+	if len(c.keys) < indexed {
+		return
+	}
+	for _, k := range c.keys {
+		if !reflect.TypeOf(k).Comparable() {
+			return
+		}
+	}
+	c.idx = make(map[any]int, len(c.keys))
+	for i, k := range c.keys {
+		c.idx[k] = i
+	}
+}
+
+func (c *batch) Value(k any) any {
+	if c.idx != nil {
+		if t := reflect.TypeOf(k); t != nil && t.Comparable() {
+			if i, ok := c.idx[k]; ok {
+				return c.vals[i]
+			}
+		}
+		return c.Context.Value(k)
+	}
+	for i := range c.keys {
+		if c.keys[i] == k {
+			return c.vals[i]
+		}
+	}
+	return c.Context.Value(k)
+}
+
+func (c *batch) String() string {
+	b := strings.Builder{}
+	b.WriteString(nameof(c.Context) + ".WithBatch(")
+	for i := range c.keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("key:" + reflect.TypeOf(c.keys[i]).String() + ", val:" + stringify(c.vals[i]))
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// same reports if c is parent without panicking on incomparable contexts
+func same(c, parent context.Context) bool {
+	return c != nil && reflect.TypeOf(c).Comparable() && c == parent
+}
@@ -0,0 +1,146 @@
+package contextx_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/xoctopus/x/contextx"
+	. "github.com/xoctopus/x/testx"
+)
+
+type tKey int
+
+func carriers(n int) []contextx.Carrier {
+	carriers := make([]contextx.Carrier, 0, n)
+	for i := range n {
+		carriers = append(carriers, func(ctx context.Context) context.Context {
+			return contextx.WithValue(ctx, tKey(i), i)
+		})
+	}
+	return carriers
+}
+
+func BenchmarkWithBatch(b *testing.B) {
+	for _, n := range []int{4, 32} {
+		var (
+			parent = context.Background()
+			std    = parent
+			kv     = parent
+			batch  = contextx.WithBatch(parent, carriers(n)...)
+		)
+		for i := range n {
+			std = context.WithValue(std, tKey(i), i)
+			kv = contextx.WithValue(kv, tKey(i), i)
+		}
+
+		b.Run(fmt.Sprintf("Value%d", n), func(b *testing.B) {
+			b.Run("std.Context", func(b *testing.B) {
+				for b.Loop() {
+					_ = std.Value(tKey(0))
+				}
+			})
+			b.Run("x.Contextx", func(b *testing.B) {
+				for b.Loop() {
+					_ = kv.Value(tKey(0))
+				}
+			})
+			b.Run("x.Batch", func(b *testing.B) {
+				for b.Loop() {
+					_ = batch.Value(tKey(0))
+				}
+			})
+		})
+	}
+
+	b.Run("Build32", func(b *testing.B) {
+		b.Run("x.Compose", func(b *testing.B) {
+			carrier := contextx.Compose(carriers(32)...)
+			for b.Loop() {
+				_ = carrier(context.Background())
+			}
+		})
+		b.Run("x.Batch", func(b *testing.B) {
+			carrier := contextx.Batch(carriers(32)...)
+			for b.Loop() {
+				_ = carrier(context.Background())
+			}
+		})
+	})
+}
+
+func ExampleWithBatch() {
+	var (
+		name = contextx.NewT[string]()
+		age  = contextx.NewT[int]()
+	)
+
+	ctx := contextx.WithBatch(
+		MockContext{},
+		name.Carry("alice"),
+		age.Carry(18),
+		contextx.Carry[tCtxT]("std"),
+		name.Carry("bob"),
+	)
+	fmt.Println(ctx)
+	fmt.Println(name.MustFrom(ctx), age.MustFrom(ctx), contextx.Must[tCtxT, string](ctx))
+
+	// Output:
+	// contextx_test.MockContext.WithBatch(key:*contextx.ctx[string], val:bob, key:contextx_test.tCtxT, val:std, key:*contextx.ctx[int], val:<not Stringer>)
+	// bob 18 std
+}
+
+func TestWithBatch(t *testing.T) {
+	t.Run("CatchParentIsNil", func(t *testing.T) {
+		ExpectPanic(t, func() { contextx.WithBatch(nil) }, NotBeNil[any]())
+	})
+	t.Run("Empty", func(t *testing.T) {
+		parent := context.Background()
+		Expect(t, contextx.WithBatch(parent), Equal(parent))
+	})
+	t.Run("Indexed", func(t *testing.T) {
+		parent := context.WithValue(context.Background(), key{}, "parent")
+		ctx := contextx.Batch(carriers(32)...)(parent)
+
+		layers := contextx.Inspect(ctx)
+		Expect(t, len(layers), Equal(34))
+		Expect(t, layers[0].Type, Equal("*contextx.batch"))
+
+		for i := range 32 {
+			Expect(t, ctx.Value(tKey(i)), Equal[any](i))
+		}
+		Expect(t, ctx.Value(key{}), Equal[any]("parent"))
+		Expect(t, ctx.Value(tKey(32)), BeNil[any]())
+		Expect(t, ctx.Value([]int{}), BeNil[any]())
+	})
+	t.Run("Nested", func(t *testing.T) {
+		inner := contextx.Batch(carriers(2)...)
+		ctx := contextx.WithBatch(context.Background(), inner, func(ctx context.Context) context.Context {
+			return contextx.WithValue(ctx, tKey(1), "override")
+		})
+		layers := contextx.Inspect(ctx)
+		Expect(t, len(layers), Equal(3))
+		Expect(t, ctx.Value(tKey(0)), Equal[any](0))
+		Expect(t, ctx.Value(tKey(1)), Equal[any]("override"))
+	})
+	t.Run("NotFlattened", func(t *testing.T) {
+		var cancel context.CancelFunc
+		ctx := contextx.WithBatch(
+			context.Background(),
+			contextx.Carry[tCtxT]("1"),
+			func(ctx context.Context) context.Context {
+				ctx, cancel = context.WithCancel(ctx)
+				return ctx
+			},
+		)
+		defer cancel()
+		Expect(t, contextx.Inspect(ctx)[0].Type, Equal("*context.cancelCtx"))
+		Expect(t, contextx.Must[tCtxT, string](ctx), Equal("1"))
+
+		replaced := contextx.WithBatch(context.Background(), func(context.Context) context.Context {
+			return contextx.WithValue(MockContext{}, key{}, 1)
+		})
+		Expect(t, replaced.Value(key{}), Equal[any](1))
+		Expect(t, contextx.Inspect(replaced)[0].Type, Equal("*contextx.kv"))
+	})
+}
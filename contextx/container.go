@@ -0,0 +1,278 @@
+package contextx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/xoctopus/x/misc/must"
+)
+
+var (
+	ErrInvalidConstructor = errors.New("invalid constructor")
+	ErrDuplicated         = errors.New("duplicated component")
+	ErrMissingDependency  = errors.New("missing dependency")
+	ErrDependencyCycle    = errors.New("dependency cycle")
+	ErrCaptiveDependency  = errors.New("singleton depends on per-context component")
+)
+
+// Lifetime is lifetime of component
+type Lifetime int
+
+const (
+	// Singleton component is constructed once when the container is built
+	Singleton Lifetime = iota
+	// PerContext component is constructed each time the Carrier is applied
+	PerContext
+)
+
+func (l Lifetime) String() string {
+	if l == PerContext {
+		return "per-context"
+	}
+	return "singleton"
+}
+
+type ProvideOption func(*component)
+
+// WithLifetime sets lifetime of component, default is Singleton
+func WithLifetime(l Lifetime) ProvideOption {
+	return func(c *component) {
+		c.lifetime = l
+	}
+}
+
+// NewContainer creates a dependency injection container
+func NewContainer() *Container {
+	return &Container{components: map[reflect.Type]*component{}}
+}
+
+// Container registers constructors of components, resolves them as a
+// dependency graph and installs them into context as a Carrier. components
+// are resolved by Resolve from the installed context. a component implements
+// Provider also installs itself by WithContext.
+type Container struct {
+	mu         sync.Mutex
+	components map[reflect.Type]*component
+	order      []reflect.Type
+}
+
+type component struct {
+	typ      reflect.Type
+	ctor     reflect.Value
+	deps     []reflect.Type
+	lifetime Lifetime
+}
+
+var tError = reflect.TypeFor[error]()
+
+// Provide registers constructor of a component. ctor must be a function
+// returns the component or the component with an error. its parameters are
+// the dependencies which are components or a context.Context, which is the
+// context being installed.
+func (c *Container) Provide(ctor any, options ...ProvideOption) error {
+	rv := reflect.ValueOf(ctor)
+	if rv.Kind() != reflect.Func || rv.IsNil() {
+		return fmt.Errorf("%w: %T", ErrInvalidConstructor, ctor)
+	}
+	rt := rv.Type()
+	if rt.IsVariadic() || rt.NumOut() == 0 || rt.NumOut() > 2 ||
+		rt.NumOut() == 2 && rt.Out(1) != tError || rt.Out(0) == tContext {
+		return fmt.Errorf("%w: %s", ErrInvalidConstructor, rt)
+	}
+
+	x := &component{typ: rt.Out(0), ctor: rv}
+	for i := range rt.NumIn() {
+		x.deps = append(x.deps, rt.In(i))
+	}
+	for _, option := range options {
+		if option != nil {
+			option(x)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.components[x.typ]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicated, x.typ)
+	}
+	c.components[x.typ] = x
+	c.order = append(c.order, x.typ)
+	return nil
+}
+
+// Build validates the dependency graph and constructs singleton components
+// with ctx. the returned Carrier installs all components into context in
+// dependency order. it panics if any per-context component failed to be
+// constructed, Install can be used to handle the error.
+func (c *Container) Build(ctx context.Context) (Carrier, error) {
+	i, err := c.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) context.Context {
+		return must.NoErrorV(i.Install(ctx))
+	}, nil
+}
+
+// Install builds the container with ctx and installs all components into ctx
+func (c *Container) Install(ctx context.Context) (context.Context, error) {
+	i, err := c.build(ctx)
+	if err != nil {
+		return ctx, err
+	}
+	return i.Install(ctx)
+}
+
+func (c *Container) build(ctx context.Context) (*installer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sorted, err := c.sort()
+	if err != nil {
+		return nil, err
+	}
+
+	singletons := make(map[reflect.Type]reflect.Value)
+	for _, x := range sorted {
+		if x.lifetime != Singleton {
+			continue
+		}
+		if ctx, err = x.install(ctx, singletons); err != nil {
+			return nil, err
+		}
+	}
+	return &installer{sorted: sorted, singletons: singletons}, nil
+}
+
+// sort returns components in dependency order. it reports missing, cyclic and
+// captive dependencies.
+func (c *Container) sort() ([]*component, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	var (
+		states = make(map[reflect.Type]int, len(c.components))
+		sorted = make([]*component, 0, len(c.components))
+		path   []reflect.Type
+		visit  func(*component) error
+	)
+
+	visit = func(x *component) error {
+		switch states[x.typ] {
+		case visited:
+			return nil
+		case visiting:
+			names := make([]string, 0, len(path)+1)
+			for _, t := range path[slices.Index(path, x.typ):] {
+				names = append(names, t.String())
+			}
+			names = append(names, x.typ.String())
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(names, " -> "))
+		}
+
+		states[x.typ] = visiting
+		path = append(path, x.typ)
+		for _, t := range x.deps {
+			if t == tContext {
+				continue
+			}
+			dep, ok := c.components[t]
+			if !ok {
+				return fmt.Errorf("%w: %s required by %s", ErrMissingDependency, t, x.typ)
+			}
+			if x.lifetime == Singleton && dep.lifetime == PerContext {
+				return fmt.Errorf("%w: %s -> %s", ErrCaptiveDependency, x.typ, t)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		states[x.typ] = visited
+		sorted = append(sorted, x)
+		return nil
+	}
+
+	for _, t := range c.order {
+		if err := visit(c.components[t]); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// install constructs x with dependencies in values and installs it into ctx
+func (x *component) install(ctx context.Context, values map[reflect.Type]reflect.Value) (context.Context, error) {
+	args := make([]reflect.Value, len(x.deps))
+	for i, t := range x.deps {
+		if t == tContext {
+			args[i] = reflect.ValueOf(&ctx).Elem()
+			continue
+		}
+		args[i] = values[t]
+	}
+
+	out := x.ctor.Call(args)
+	if len(out) == 2 && !out[1].IsNil() {
+		return ctx, fmt.Errorf("construct %s: %w", x.typ, out[1].Interface().(error))
+	}
+	values[x.typ] = out[0]
+	return installed(ctx, x.typ, out[0]), nil
+}
+
+// installed carries component v typed t into ctx. a nil pointer component is
+// carried but not installed by WithContext even if it implements Provider.
+func installed(ctx context.Context, t reflect.Type, v reflect.Value) context.Context {
+	x := v.Interface()
+	ctx = WithValue(ctx, tCtxComponent{t}, x)
+	if p, ok := x.(Provider); ok {
+		if rv := reflect.ValueOf(x); rv.Kind() != reflect.Pointer || !rv.IsNil() {
+			ctx = p.WithContext(ctx)
+		}
+	}
+	return ctx
+}
+
+type installer struct {
+	sorted     []*component
+	singletons map[reflect.Type]reflect.Value
+}
+
+func (i *installer) Install(ctx context.Context) (context.Context, error) {
+	values := make(map[reflect.Type]reflect.Value, len(i.sorted))
+	for _, x := range i.sorted {
+		if v, ok := i.singletons[x.typ]; ok {
+			values[x.typ] = v
+			ctx = installed(ctx, x.typ, v)
+			continue
+		}
+		var err error
+		if ctx, err = x.install(ctx, values); err != nil {
+			return ctx, err
+		}
+	}
+	return ctx, nil
+}
+
+type tCtxComponent struct {
+	typ reflect.Type
+}
+
+// Resolve returns the component typed T installed by Container
+func Resolve[T any](ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(tCtxComponent{reflect.TypeFor[T]()}).(T)
+	return v, ok
+}
+
+// MustResolve likes Resolve, but panics if component T is not installed
+func MustResolve[T any](ctx context.Context) T {
+	v, ok := Resolve[T](ctx)
+	must.BeTrueF(ok, "component %s not found in context", reflect.TypeFor[T]())
+	return v
+}
@@ -0,0 +1,142 @@
+package contextx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/xoctopus/x/contextx"
+	. "github.com/xoctopus/x/testx"
+)
+
+type Config struct{ DSN string }
+
+type DB struct{ *Config }
+
+type Repo struct {
+	DB    *DB
+	Trace string
+}
+
+type tCtxDB struct{}
+
+// WithContext also carries DB as a std context value
+func (db *DB) WithContext(ctx context.Context) context.Context {
+	return contextx.With[tCtxDB](ctx, db)
+}
+
+func ExampleContainer() {
+	trace := contextx.NewT[string]()
+
+	c := contextx.NewContainer()
+	_ = c.Provide(func() *Config { return &Config{DSN: "postgres://"} })
+	_ = c.Provide(func(c *Config) (*DB, error) { return &DB{c}, nil })
+	_ = c.Provide(func(ctx context.Context, db *DB) *Repo {
+		return &Repo{DB: db, Trace: trace.MustFrom(ctx)}
+	}, contextx.WithLifetime(contextx.PerContext))
+
+	carrier, err := c.Build(context.Background())
+	if err != nil {
+		return
+	}
+
+	ctx1 := carrier(trace.With(context.Background(), "trace-1"))
+	ctx2 := carrier(trace.With(context.Background(), "trace-2"))
+
+	repo1 := contextx.MustResolve[*Repo](ctx1)
+	repo2 := contextx.MustResolve[*Repo](ctx2)
+	fmt.Println(repo1.DB.DSN, repo1.Trace, repo2.Trace)
+	fmt.Println(repo1 != repo2, repo1.DB == repo2.DB)
+	fmt.Println(contextx.Must[tCtxDB, *DB](ctx1) == repo1.DB)
+
+	// Output:
+	// postgres:// trace-1 trace-2
+	// true true
+	// true
+}
+
+func TestContainer(t *testing.T) {
+	t.Run("InvalidConstructor", func(t *testing.T) {
+		c := contextx.NewContainer()
+		for _, ctor := range []any{
+			nil,
+			1,
+			(func() int)(nil),
+			func() {},
+			func(...int) int { return 0 },
+			func() (int, int) { return 0, 0 },
+			func() (int, int, error) { return 0, 0, nil },
+			func() context.Context { return nil },
+		} {
+			Expect(t, c.Provide(ctor), IsError(contextx.ErrInvalidConstructor))
+		}
+	})
+	t.Run("Duplicated", func(t *testing.T) {
+		c := contextx.NewContainer()
+		Expect(t, c.Provide(func() int { return 1 }), Succeed())
+		Expect(t, c.Provide(func() (int, error) { return 2, nil }), IsError(contextx.ErrDuplicated))
+	})
+	t.Run("MissingDependency", func(t *testing.T) {
+		c := contextx.NewContainer()
+		Expect(t, c.Provide(func(string) int { return 1 }), Succeed())
+		_, err := c.Build(context.Background())
+		Expect(t, err, IsError(contextx.ErrMissingDependency))
+		Expect(t, err, ErrorContains("string required by int"))
+	})
+	t.Run("DependencyCycle", func(t *testing.T) {
+		c := contextx.NewContainer()
+		Expect(t, c.Provide(func(int) string { return "" }), Succeed())
+		Expect(t, c.Provide(func(float64) int { return 1 }), Succeed())
+		Expect(t, c.Provide(func(string) float64 { return 1 }), Succeed())
+		_, err := c.Install(context.Background())
+		Expect(t, err, IsError(contextx.ErrDependencyCycle))
+		Expect(t, err, ErrorContains("string -> int -> float64 -> string"))
+	})
+	t.Run("CaptiveDependency", func(t *testing.T) {
+		c := contextx.NewContainer()
+		Expect(t, c.Provide(func() int { return 1 }, contextx.WithLifetime(contextx.PerContext)), Succeed())
+		Expect(t, c.Provide(func(int) string { return "" }, nil), Succeed())
+		_, err := c.Build(context.Background())
+		Expect(t, err, IsError(contextx.ErrCaptiveDependency))
+		Expect(t, contextx.PerContext.String(), Equal("per-context"))
+		Expect(t, contextx.Singleton.String(), Equal("singleton"))
+	})
+	t.Run("ConstructFailed", func(t *testing.T) {
+		failed := errors.New("failed")
+		c := contextx.NewContainer()
+		Expect(t, c.Provide(func() (int, error) { return 0, failed }), Succeed())
+		_, err := c.Build(context.Background())
+		Expect(t, err, IsError(failed))
+
+		c = contextx.NewContainer()
+		Expect(t, c.Provide(func() (int, error) { return 0, failed }, contextx.WithLifetime(contextx.PerContext)), Succeed())
+		carrier, err := c.Build(context.Background())
+		Expect(t, err, Succeed())
+		ExpectPanic[error](t, func() { carrier(context.Background()) })
+		_, err = c.Install(context.Background())
+		Expect(t, err, IsError(failed))
+	})
+	t.Run("Install", func(t *testing.T) {
+		c := contextx.NewContainer()
+		Expect(t, c.Provide(func() int { return 1 }), Succeed())
+		Expect(t, c.Provide(func(i int) string { return fmt.Sprint(i) }, contextx.WithLifetime(contextx.PerContext)), Succeed())
+		ctx, err := c.Install(context.Background())
+		Expect(t, err, Succeed())
+		Expect(t, contextx.MustResolve[int](ctx), Equal(1))
+		Expect(t, contextx.MustResolve[string](ctx), Equal("1"))
+		_, ok := contextx.Resolve[float64](ctx)
+		Expect(t, ok, BeFalse())
+		ExpectPanic[error](t, func() { contextx.MustResolve[float64](ctx) })
+	})
+	t.Run("NilProvider", func(t *testing.T) {
+		c := contextx.NewContainer()
+		Expect(t, c.Provide(func() *DB { return nil }), Succeed())
+		Expect(t, c.Provide(func(db *DB) contextx.Provider { return db }), Succeed())
+		ctx, err := c.Install(context.Background())
+		Expect(t, err, Succeed())
+		Expect(t, contextx.MustResolve[*DB](ctx), BeNil[*DB]())
+		_, ok := contextx.From[tCtxDB, *DB](ctx)
+		Expect(t, ok, BeFalse())
+	})
+}
@@ -2,7 +2,7 @@ package contextx
 
 import (
 	"context"
-	"sync"
+	"sync/atomic"
 
 	"github.com/xoctopus/x/misc/must"
 )
@@ -18,6 +18,10 @@ func WithDefault[T any](v T) Option[T] {
 	}
 }
 
+// WithOnce makes Context[T] a singleton value mode. the value of the first
+// With or Carry is cached and later ones carry the cached value into their own
+// parents, so values passed afterward are ignored. it is safe for concurrent
+// use, the first one who stores the value wins. Resetter drops the cached value.
 func WithOnce[T any]() Option[T] {
 	return func(c *ctx[T]) {
 		c.once = true
@@ -47,26 +51,60 @@ type Context[T any] interface {
 	Carry(v T) Carrier
 }
 
+// Accessor accesses the value carried by context directly, without the default
+// value or the cached value of once mode. it is implemented by Context[T]
+// created by NewT and NewV.
+type Accessor[T any] interface {
+	// Lookup returns the value carried by ctx, the default value is ignored
+	Lookup(context.Context) (T, bool)
+	// Store carries v into ctx as it is, the cached value of once mode is
+	// neither used nor changed
+	Store(context.Context, T) context.Context
+}
+
+// Resetter is implemented by Context[T] created by NewT and NewV
+type Resetter interface {
+	// Reset drops the cached value in once mode, contexts already carrying it
+	// are not affected. it is useful in tests.
+	Reset()
+}
+
 type ctx[T any] struct {
 	defaulter Valuer[T]
 	once      bool
-	with      func() context.Context
+	cached    atomic.Pointer[T]
 }
 
 func (c *ctx[T]) With(ctx context.Context, v T) context.Context {
 	if c.once {
-		if c.with == nil {
-			c.with = sync.OnceValue(func() context.Context {
-				return WithValue(ctx, c, v)
-			})
+		for {
+			if p := c.cached.Load(); p != nil {
+				v = *p
+				break
+			}
+			if c.cached.CompareAndSwap(nil, &v) {
+				break
+			}
 		}
-		return c.with()
 	}
 	return WithValue(ctx, c, v)
 }
 
+func (c *ctx[T]) Reset() {
+	c.cached.Store(nil)
+}
+
+func (c *ctx[T]) Lookup(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(c).(T)
+	return v, ok
+}
+
+func (c *ctx[T]) Store(ctx context.Context, v T) context.Context {
+	return WithValue(ctx, c, v)
+}
+
 func (c *ctx[T]) From(ctx context.Context) (T, bool) {
-	if v, ok := ctx.Value(c).(T); ok {
+	if v, ok := c.Lookup(ctx); ok {
 		return v, ok
 	}
 	if c.defaulter != nil {
@@ -88,6 +126,16 @@ func (c *ctx[T]) Carry(v T) Carrier {
 	}
 }
 
+func (c *ctx[T]) Copy(src context.Context) Carrier {
+	v, ok := c.Lookup(src)
+	return func(ctx context.Context) context.Context {
+		if !ok {
+			return ctx
+		}
+		return c.Store(ctx, v)
+	}
+}
+
 type Carrier func(context.Context) context.Context
 
 func Compose(carriers ...Carrier) Carrier {
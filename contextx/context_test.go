@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sync"
 	"testing"
 
 	"github.com/xoctopus/x/contextx"
@@ -56,6 +57,66 @@ func TestContext(t *testing.T) {
 			ctx = c.With(context.Background(), "replace")
 			Expect(t, c.MustFrom(ctx), Equal(t.Name()))
 		})
+		t.Run("OncePerParent", func(t *testing.T) {
+			type tCtxParent struct{}
+
+			c := contextx.NewT(contextx.WithOnce[string]())
+			p1 := contextx.With[tCtxParent](context.Background(), "p1")
+			p2 := contextx.With[tCtxParent](context.Background(), "p2")
+
+			ctx1 := c.With(p1, "v1")
+			ctx2 := c.Carry("v2")(p2)
+			Expect(t, c.MustFrom(ctx1), Equal("v1"))
+			Expect(t, c.MustFrom(ctx2), Equal("v1"))
+			Expect(t, contextx.Must[tCtxParent, string](ctx1), Equal("p1"))
+			Expect(t, contextx.Must[tCtxParent, string](ctx2), Equal("p2"))
+
+			c.(contextx.Resetter).Reset()
+			ctx3 := c.With(p2, "v3")
+			Expect(t, c.MustFrom(ctx3), Equal("v3"))
+			Expect(t, c.MustFrom(c.With(p1, "v4")), Equal("v3"))
+			Expect(t, c.MustFrom(ctx1), Equal("v1"))
+
+			c = contextx.NewT[string]()
+			c.(contextx.Resetter).Reset()
+			Expect(t, c.MustFrom(c.With(p1, "v5")), Equal("v5"))
+		})
+		t.Run("OnceConcurrent", func(t *testing.T) {
+			c := contextx.NewT(contextx.WithOnce[int]())
+			values := make([]int, 100)
+			wg := sync.WaitGroup{}
+			for i := range values {
+				wg.Go(func() { values[i] = c.MustFrom(c.With(context.Background(), i)) })
+			}
+			wg.Wait()
+			for i := range values {
+				Expect(t, values[i], Equal(values[0]))
+			}
+
+			for i := range values {
+				wg.Go(func() {
+					if i%10 == 0 {
+						c.(contextx.Resetter).Reset()
+					}
+					_ = c.With(context.Background(), i)
+				})
+			}
+			wg.Wait()
+		})
+	})
+	t.Run("Accessor", func(t *testing.T) {
+		c := contextx.NewT(contextx.WithOnce[string](), contextx.WithDefault("default"))
+		a := c.(contextx.Accessor[string])
+
+		_, ok := a.Lookup(context.Background())
+		Expect(t, ok, BeFalse())
+
+		cached := c.With(context.Background(), "cached")
+		ctx := a.Store(cached, "stored")
+		val, ok := a.Lookup(ctx)
+		Expect(t, ok, BeTrue())
+		Expect(t, val, Equal("stored"))
+		Expect(t, c.MustFrom(c.With(context.Background(), "any")), Equal("cached"))
 	})
 }
 
@@ -0,0 +1,71 @@
+package contextx
+
+import (
+	"context"
+)
+
+// Copier copies the value of a key from a context
+type Copier interface {
+	// Copy returns a Carrier carries the value of key carried by src. the
+	// Carrier does nothing if src does not carry the value. default value is
+	// not copied.
+	Copy(src context.Context) Carrier
+}
+
+// CopierT returns a Copier of std context key typed KT, which is carried by
+// With or Carry.
+func CopierT[KT comparable]() Copier {
+	return copierT[KT]{}
+}
+
+type copierT[KT comparable] struct{}
+
+func (copierT[KT]) Copy(src context.Context) Carrier {
+	v := src.Value(*new(KT))
+	return func(ctx context.Context) context.Context {
+		if v == nil {
+			return ctx
+		}
+		return context.WithValue(ctx, *new(KT), v)
+	}
+}
+
+// CopierOf returns a Copier of c. Context[T] created by NewT or NewV copies
+// the carried value only, other implementations copy the value found by From.
+func CopierOf[T any](c Context[T]) Copier {
+	if x, ok := c.(Copier); ok {
+		return x
+	}
+	return copierOf[T]{c}
+}
+
+type copierOf[T any] struct {
+	c Context[T]
+}
+
+func (x copierOf[T]) Copy(src context.Context) Carrier {
+	v, ok := x.c.From(src)
+	return func(ctx context.Context) context.Context {
+		if !ok {
+			return ctx
+		}
+		return x.c.With(ctx, v)
+	}
+}
+
+// Detach returns a context is never canceled and has no deadline for
+// background work spawned from ctx. if keys is empty, it keeps all values of
+// ctx as context.WithoutCancel. otherwise, only values of keys are copied
+// into a fresh context. values of Context[T] are copied by CopierOf.
+func Detach(ctx context.Context, keys ...Copier) context.Context {
+	if len(keys) == 0 {
+		return context.WithoutCancel(ctx)
+	}
+	carriers := make([]Carrier, 0, len(keys))
+	for _, k := range keys {
+		if k != nil {
+			carriers = append(carriers, k.Copy(ctx))
+		}
+	}
+	return WithBatch(context.Background(), carriers...)
+}
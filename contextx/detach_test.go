@@ -0,0 +1,77 @@
+package contextx_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xoctopus/x/contextx"
+	. "github.com/xoctopus/x/testx"
+)
+
+type tCtxTenant struct{}
+
+func ExampleDetach() {
+	trace := contextx.NewT[string]()
+	user := contextx.NewT[string]()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	ctx = contextx.Compose(
+		trace.Carry("trace-1"),
+		user.Carry("alice"),
+		contextx.Carry[tCtxTenant]("tenant-1"),
+	)(ctx)
+	cancel()
+
+	detached := contextx.Detach(ctx, contextx.CopierOf(trace), contextx.CopierT[tCtxTenant]())
+	_, deadline := detached.Deadline()
+	_, carried := user.From(detached)
+
+	fmt.Println(detached.Err(), deadline, carried)
+	fmt.Println(trace.MustFrom(detached), contextx.Must[tCtxTenant, string](detached))
+
+	// Output:
+	// <nil> false false
+	// trace-1 tenant-1
+}
+
+func TestDetach(t *testing.T) {
+	trace := contextx.NewT[string]()
+	ctx, cancel := context.WithCancel(trace.With(context.Background(), "trace"))
+	cancel()
+
+	t.Run("KeepAll", func(t *testing.T) {
+		detached := contextx.Detach(ctx)
+		Expect(t, detached.Err(), Succeed())
+		Expect(t, trace.MustFrom(detached), Equal("trace"))
+	})
+	t.Run("NotCarried", func(t *testing.T) {
+		name := contextx.NewV("default")
+		detached := contextx.Detach(ctx, contextx.CopierOf(name), contextx.CopierT[tCtxTenant](), nil)
+		Expect(t, detached, Equal(context.Background()))
+		Expect(t, name.MustFrom(detached), Equal("default"))
+	})
+	t.Run("Once", func(t *testing.T) {
+		once := contextx.NewT(contextx.WithOnce[int]())
+		src := once.With(context.Background(), 1)
+
+		detached := contextx.Detach(src, contextx.CopierOf(once))
+		Expect(t, detached.Done(), BeNil[<-chan struct{}]())
+		Expect(t, once.MustFrom(detached), Equal(1))
+	})
+	t.Run("CustomContext", func(t *testing.T) {
+		c := tCtxCustom{contextx.NewT[string]()}
+		src, cancel := context.WithCancel(c.With(context.Background(), "custom"))
+		cancel()
+
+		detached := contextx.Detach(src, contextx.CopierOf[string](c))
+		Expect(t, detached.Err(), Succeed())
+		Expect(t, c.MustFrom(detached), Equal("custom"))
+	})
+}
+
+// tCtxCustom implements Context[string] without Copier
+type tCtxCustom struct {
+	contextx.Context[string]
+}
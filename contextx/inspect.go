@@ -0,0 +1,171 @@
+package contextx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Layer describes a layer of context chain
+type Layer struct {
+	// Type is the type name of context layer. eg: `*context.cancelCtx`
+	Type string
+	// Key is the key of value carried by layer, nil if layer carries no value
+	Key any
+	// Value is the value carried by layer
+	Value any
+	// Shadowed reports if Key is overridden by an outer layer
+	Shadowed bool
+}
+
+// HasValue reports if layer carries a value
+func (l Layer) HasValue() bool {
+	return l.Key != nil
+}
+
+// KeyType returns type of Key, nil if layer carries no value
+func (l Layer) KeyType() reflect.Type {
+	return reflect.TypeOf(l.Key)
+}
+
+func (l Layer) String() string {
+	if !l.HasValue() {
+		return l.Type
+	}
+	s := l.Type + " key=" + l.KeyType().String() + " value=" + describe(l.Value)
+	if l.Shadowed {
+		s += " (shadowed)"
+	}
+	return s
+}
+
+// Layers is a context chain ordered from the outermost layer to the root
+type Layers []Layer
+
+// Values returns layers carrying values which are visible from the outermost
+// context
+func (ls Layers) Values() Layers {
+	values := make(Layers, 0, len(ls))
+	for _, l := range ls {
+		if l.HasValue() && !l.Shadowed {
+			values = append(values, l)
+		}
+	}
+	return values
+}
+
+// String pretty-prints layers one per line with its order
+func (ls Layers) String() string {
+	b := strings.Builder{}
+	for i, l := range ls {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString("#" + strconv.Itoa(i) + " " + l.String())
+	}
+	return b.String()
+}
+
+// Inspect walks the chain of ctx from ctx itself to the root, and returns the
+// layers with the values they carry. values carried by WithValue, WithBatch
+// and std context.WithValue are all inspected. the walk stops at a layer whose
+// parent cannot be found, such as a third-party context.
+func Inspect(ctx context.Context) Layers {
+	var (
+		layers Layers
+		seen   = map[any]struct{}{}
+	)
+	add := func(l Layer) {
+		if l.HasValue() && l.KeyType().Comparable() {
+			_, l.Shadowed = seen[l.Key]
+			seen[l.Key] = struct{}{}
+		}
+		layers = append(layers, l)
+	}
+	for ctx != nil {
+		l := Layer{Type: reflect.TypeOf(ctx).String()}
+		switch c := ctx.(type) {
+		case *kv:
+			l.Key, l.Value = c.k, c.v
+			add(l)
+			ctx = c.Context
+		case *batch:
+			// each value of batch is listed as a layer
+			for i := range c.keys {
+				l.Key, l.Value = c.keys[i], c.vals[i]
+				add(l)
+			}
+			ctx = c.Context
+		default:
+			ctx = unwrap(ctx, &l)
+			add(l)
+		}
+	}
+	return layers
+}
+
+var tContext = reflect.TypeFor[context.Context]()
+
+// unwrap finds parent of std or unknown context by reflection. key and value
+// of std valueCtx are filled into l.
+func unwrap(ctx context.Context, l *Layer) context.Context {
+	rv := reflect.ValueOf(ctx)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	if !rv.CanAddr() {
+		// copy to make unexported fields readable
+		cp := reflect.New(rv.Type()).Elem()
+		cp.Set(rv)
+		rv = cp
+	}
+
+	if l.Type == "*context.valueCtx" {
+		k, v := rv.FieldByName("key"), rv.FieldByName("val")
+		if k.IsValid() && v.IsValid() {
+			l.Key, l.Value = readable(k).Interface(), readable(v).Interface()
+		}
+	}
+	parent, _ := field(rv).(context.Context)
+	return parent
+}
+
+// field returns the first context.Context field of struct rv and its embedded
+// structs
+func field(rv reflect.Value) any {
+	for i := range rv.NumField() {
+		f := rv.Field(i)
+		if rv.Type().Field(i).Type == tContext {
+			return readable(f).Interface()
+		}
+	}
+	for i := range rv.NumField() {
+		if sf := rv.Type().Field(i); sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if c := field(rv.Field(i)); c != nil {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// readable makes addressable unexported field readable
+func readable(f reflect.Value) reflect.Value {
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
+
+func describe(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprintf("%v", v)
+}
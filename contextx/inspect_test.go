@@ -0,0 +1,65 @@
+package contextx_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xoctopus/x/contextx"
+	. "github.com/xoctopus/x/testx"
+)
+
+type tCtxUser struct{}
+
+func ExampleInspect() {
+	ctx := contextx.WithValue(context.Background(), tCtxUser{}, "alice")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = context.WithValue(ctx, key{}, 100)
+	ctx = contextx.WithValue(ctx, tCtxUser{}, "bob")
+
+	fmt.Println(contextx.Inspect(ctx))
+	fmt.Println(contextx.Inspect(ctx).Values())
+
+	// Output:
+	// #0 *contextx.kv key=contextx_test.tCtxUser value="bob"
+	// #1 *context.valueCtx key=contextx_test.key value=100
+	// #2 *context.cancelCtx
+	// #3 *contextx.kv key=contextx_test.tCtxUser value="alice" (shadowed)
+	// #4 context.backgroundCtx
+	// #0 *contextx.kv key=contextx_test.tCtxUser value="bob"
+	// #1 *context.valueCtx key=contextx_test.key value=100
+}
+
+func TestInspect(t *testing.T) {
+	t.Run("StdContexts", func(t *testing.T) {
+		c := contextx.NewT[int]()
+		ctx := c.With(context.TODO(), 1)
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		ctx = context.WithoutCancel(ctx)
+
+		layers := contextx.Inspect(ctx)
+		Expect(t, len(layers), Equal(4))
+		Expect(t, layers[0].Type, Equal("context.withoutCancelCtx"))
+		Expect(t, layers[1].Type, Equal("*context.timerCtx"))
+		Expect(t, layers[2].Value, Equal[any](1))
+		Expect(t, layers[2].Key, Equal[any](c))
+		Expect(t, layers[2].KeyType().String(), Equal("*contextx.ctx[int]"))
+		Expect(t, layers[3].Type, Equal("context.todoCtx"))
+		Expect(t, layers[3].HasValue(), BeFalse())
+	})
+	t.Run("OpaqueContext", func(t *testing.T) {
+		ctx := contextx.WithValue(MockContext{}, key{}, []int{1})
+		layers := contextx.Inspect(ctx)
+		Expect(t, len(layers), Equal(2))
+		Expect(t, layers.String(), Equal(
+			"#0 *contextx.kv key=contextx_test.key value=[1]\n"+
+				"#1 contextx_test.MockContext",
+		))
+	})
+	t.Run("Nil", func(t *testing.T) {
+		Expect(t, contextx.Inspect(nil), HaveLen[contextx.Layers](0))
+	})
+}
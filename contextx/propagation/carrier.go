@@ -0,0 +1,33 @@
+package propagation
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// TextMapCarrier is the storage of propagated values, such as http headers
+type TextMapCarrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// HeaderCarrier adapts http.Header as a TextMapCarrier
+type HeaderCarrier http.Header
+
+func (c HeaderCarrier) Get(key string) string { return http.Header(c).Get(key) }
+
+func (c HeaderCarrier) Set(key, value string) { http.Header(c).Set(key, value) }
+
+// ValuesCarrier adapts url.Values as a TextMapCarrier
+type ValuesCarrier url.Values
+
+func (c ValuesCarrier) Get(key string) string { return url.Values(c).Get(key) }
+
+func (c ValuesCarrier) Set(key, value string) { url.Values(c).Set(key, value) }
+
+// MapCarrier adapts map as a TextMapCarrier
+type MapCarrier map[string]string
+
+func (c MapCarrier) Get(key string) string { return c[key] }
+
+func (c MapCarrier) Set(key, value string) { c[key] = value }
@@ -0,0 +1,56 @@
+package propagation
+
+import (
+	"encoding/base64"
+
+	"github.com/xoctopus/x/textx"
+)
+
+// Codec encodes and decodes values of T for propagation
+type Codec[T any] interface {
+	Marshal(T) ([]byte, error)
+	Unmarshal([]byte) (T, error)
+}
+
+// Text returns a Codec encodes values by textx.Marshal and textx.Unmarshal
+func Text[T any]() Codec[T] {
+	return text[T]{}
+}
+
+type text[T any] struct{}
+
+func (text[T]) Marshal(v T) ([]byte, error) {
+	return textx.Marshal(v)
+}
+
+func (text[T]) Unmarshal(data []byte) (T, error) {
+	var v T
+	err := textx.Unmarshal(data, &v)
+	return v, err
+}
+
+// Base64 wraps a binary codec, so that encoded values are safe to be carried in
+// headers or queries.
+func Base64[T any](codec Codec[T]) Codec[T] {
+	return b64[T]{codec}
+}
+
+type b64[T any] struct {
+	Codec[T]
+}
+
+func (c b64[T]) Marshal(v T) ([]byte, error) {
+	data, err := c.Codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return base64.RawURLEncoding.AppendEncode(nil, data), nil
+}
+
+func (c b64[T]) Unmarshal(data []byte) (T, error) {
+	decoded, err := base64.RawURLEncoding.AppendDecode(nil, data)
+	if err != nil {
+		return *new(T), err
+	}
+	return c.Codec.Unmarshal(decoded)
+}
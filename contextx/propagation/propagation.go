@@ -0,0 +1,111 @@
+// Package propagation propagates values carried by contextx.Context across
+// process boundaries, such as http headers, url queries or message metadata.
+package propagation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/xoctopus/x/contextx"
+	"github.com/xoctopus/x/misc/must"
+	"github.com/xoctopus/x/syncx"
+)
+
+type field struct {
+	inject  func(context.Context) (string, bool, error)
+	extract func(context.Context, string) (context.Context, error)
+}
+
+var fields = syncx.NewXmap[string, *field]()
+
+// Register registers c to be propagated with name by codec. if codec is nil,
+// values are encoded by Text codec. registering the same name again overrides
+// the previous one. c must implement contextx.Accessor, the default value is
+// not injected and the extracted value is carried as it is in once mode.
+func Register[T any](name string, c contextx.Context[T], codec Codec[T]) {
+	a, ok := c.(contextx.Accessor[T])
+	must.BeTrueF(ok, "%T is not a contextx.Accessor", c)
+	if codec == nil {
+		codec = Text[T]()
+	}
+	fields.Store(name, &field{
+		inject: func(ctx context.Context) (string, bool, error) {
+			v, ok := a.Lookup(ctx)
+			if !ok {
+				return "", false, nil
+			}
+			data, err := codec.Marshal(v)
+			return string(data), err == nil, err
+		},
+		extract: func(ctx context.Context, s string) (context.Context, error) {
+			v, err := codec.Unmarshal([]byte(s))
+			if err != nil {
+				return ctx, err
+			}
+			return a.Store(ctx, v), nil
+		},
+	})
+}
+
+// Unregister removes the registered name
+func Unregister(name string) {
+	fields.Delete(name)
+}
+
+// Names returns sorted registered names
+func Names() []string {
+	names := fields.Keys()
+	slices.Sort(names)
+	return names
+}
+
+// Inject encodes values carried by ctx into carrier. if names is empty, all
+// registered values are injected. values which ctx does not carry are skipped.
+func Inject(ctx context.Context, carrier TextMapCarrier, names ...string) error {
+	var errs []error
+	for _, name := range selected(names) {
+		f, ok := fields.Load(name)
+		if !ok {
+			continue
+		}
+		s, ok, err := f.inject(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("inject `%s`: %w", name, err))
+		}
+		if ok {
+			carrier.Set(name, s)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Extract decodes values in carrier and returns a context carries them. if
+// names is empty, all registered values are extracted. values which failed to
+// decode are skipped and reported by the returned error.
+func Extract(ctx context.Context, carrier TextMapCarrier, names ...string) (context.Context, error) {
+	var errs []error
+	for _, name := range selected(names) {
+		f, ok := fields.Load(name)
+		if !ok {
+			continue
+		}
+		s := carrier.Get(name)
+		if s == "" {
+			continue
+		}
+		var err error
+		if ctx, err = f.extract(ctx, s); err != nil {
+			errs = append(errs, fmt.Errorf("extract `%s`: %w", name, err))
+		}
+	}
+	return ctx, errors.Join(errs...)
+}
+
+func selected(names []string) []string {
+	if len(names) == 0 {
+		return Names()
+	}
+	return names
+}
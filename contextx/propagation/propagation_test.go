@@ -0,0 +1,117 @@
+package propagation_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/xoctopus/x/contextx"
+	. "github.com/xoctopus/x/contextx/propagation"
+	. "github.com/xoctopus/x/testx"
+)
+
+type Tenant struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type JSON[T any] struct{}
+
+func (JSON[T]) Marshal(v T) ([]byte, error) { return json.Marshal(v) }
+
+func (JSON[T]) Unmarshal(data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+var (
+	traceID = contextx.NewT[string]()
+	retries = contextx.NewV(3)
+	tenant  = contextx.NewT[Tenant]()
+)
+
+func init() {
+	Register("X-Trace-Id", traceID, nil)
+	Register("X-Retries", retries, Text[int]())
+	Register("X-Tenant", tenant, Base64[Tenant](JSON[Tenant]{}))
+}
+
+func Example() {
+	ctx := contextx.Compose(
+		traceID.Carry("trace-1"),
+		tenant.Carry(Tenant{ID: 1, Name: "xoctopus"}),
+	)(context.Background())
+
+	header := http.Header{}
+	_ = Inject(ctx, HeaderCarrier(header))
+	fmt.Println(header)
+
+	ctx, _ = Extract(context.Background(), HeaderCarrier(header))
+	fmt.Println(traceID.MustFrom(ctx), tenant.MustFrom(ctx), retries.MustFrom(ctx))
+
+	// Output:
+	// map[X-Tenant:[eyJpZCI6MSwibmFtZSI6InhvY3RvcHVzIn0] X-Trace-Id:[trace-1]]
+	// trace-1 {1 xoctopus} 3
+}
+
+func TestPropagation(t *testing.T) {
+	t.Run("Selected", func(t *testing.T) {
+		ctx := contextx.Compose(traceID.Carry("trace"), retries.Carry(5))(context.Background())
+
+		values := url.Values{}
+		Expect(t, Inject(ctx, ValuesCarrier(values), "X-Retries", "Unknown"), Succeed())
+		Expect(t, values.Encode(), Equal("X-Retries=5"))
+
+		m := MapCarrier{"X-Trace-Id": "trace", "X-Retries": "6"}
+		ctx, err := Extract(context.Background(), m, "X-Retries")
+		Expect(t, err, Succeed())
+		Expect(t, retries.MustFrom(ctx), Equal(6))
+		_, ok := traceID.From(ctx)
+		Expect(t, ok, BeFalse())
+	})
+	t.Run("Failed", func(t *testing.T) {
+		m := MapCarrier{"X-Trace-Id": "trace", "X-Retries": "x", "X-Tenant": "!"}
+		ctx, err := Extract(context.Background(), m)
+		Expect(t, err, ErrorContains("extract `X-Retries`"))
+		Expect(t, err, ErrorContains("extract `X-Tenant`"))
+		Expect(t, traceID.MustFrom(ctx), Equal("trace"))
+		Expect(t, retries.MustFrom(ctx), Equal(3))
+
+		type Chan chan int
+		ch := contextx.NewT[Chan]()
+		Register("X-Chan", ch, nil)
+		defer Unregister("X-Chan")
+
+		m = MapCarrier{}
+		err = Inject(ch.With(context.Background(), make(Chan)), m)
+		Expect(t, err, ErrorContains("inject `X-Chan`"))
+		Expect(t, m, HaveLen[MapCarrier](0))
+	})
+	t.Run("Once", func(t *testing.T) {
+		once := contextx.NewT(contextx.WithOnce[string]())
+		Register("X-Once", once, nil)
+		defer Unregister("X-Once")
+
+		local := once.With(context.Background(), "local")
+		ctx, err := Extract(local, MapCarrier{"X-Once": "remote"}, "X-Once")
+		Expect(t, err, Succeed())
+		Expect(t, once.MustFrom(ctx), Equal("remote"))
+		Expect(t, once.MustFrom(once.With(context.Background(), "any")), Equal("local"))
+
+		m := MapCarrier{}
+		Expect(t, Inject(ctx, m, "X-Once"), Succeed())
+		Expect(t, m, Equal(MapCarrier{"X-Once": "remote"}))
+	})
+	t.Run("NotAccessor", func(t *testing.T) {
+		ExpectPanic[error](t, func() {
+			Register("X-Custom", struct{ contextx.Context[int] }{retries}, nil)
+		})
+	})
+	t.Run("Names", func(t *testing.T) {
+		Expect(t, Names(), Equal([]string{"X-Retries", "X-Tenant", "X-Trace-Id"}))
+	})
+}
@@ -1,8 +1,9 @@
 package cleanup
 
 import (
-	"errors"
 	"sync"
+
+	"github.com/xoctopus/x/codex"
 )
 
 type Collector interface {
@@ -45,7 +46,7 @@ func (c *collector) JoinTo(dst *error) error {
 		if len(errs) == 0 {
 			c.final = err
 		} else {
-			c.final = errors.Join(errs...)
+			c.final = codex.Join(errs...)
 		}
 
 		if dst != nil {